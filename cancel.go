@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"opensea-bot/pkg"
+	"strings"
+)
+
+func cancelCommand(args []string) {
+	fs := flag.NewFlagSet("cancel", flag.ExitOnError)
+	contract := fs.String("contract", "", "nft contract address the orders belong to")
+	chain := fs.String("chain", "ethereum", "chain of the contract")
+	hashes := fs.String("hash", "", "comma separated order hashes to cancel")
+	onChain := fs.Bool("onchain", false, "cancel every order with a Seaport transaction, by default only the orders OpenSea cannot cancel off-chain are")
+	all := fs.Bool("all", false, "invalidate every order of the wallet by incrementing the Seaport counter")
+	rpc := fs.String("rpc", "", "comma separated rpc endpoints, defaults to the chain's endpoint")
+	keystore := fs.String("keystore", "", "geth keystore file of the wallet, the passphrase is prompted for")
+	external := fs.String("signer", "", "endpoint of a Clef compatible external signer")
+	_ = fs.Parse(args)
+
+	ctx := context.Background()
+	var endpoints []string
+	if *rpc != "" {
+		endpoints = strings.Split(*rpc, ",")
+	}
+	opts := []pkg.Option{pkg.WithContract(*contract), pkg.WithChain(*chain), pkg.WithRPC(endpoints...)}
+	if *keystore != "" || *external != "" {
+		signer, err := pkg.OpenSigner(ctx, &pkg.WalletConfig{Keystore: *keystore, External: *external})
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, pkg.WithSigner(signer))
+	}
+	account, err := pkg.NewAccount(ctx, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer account.Close()
+
+	if *all {
+		receipt, err := account.CancelAll(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("all orders cancelled in block %s", receipt.BlockNumber)
+		return
+	}
+
+	if *hashes == "" {
+		log.Fatal("nothing to cancel, pass -hash or -all")
+	}
+	orderHashes := strings.Split(*hashes, ",")
+
+	for i := range orderHashes {
+		orderHashes[i] = strings.TrimSpace(orderHashes[i])
+	}
+
+	if !*onChain {
+		receipt, err := account.CancelByHash(ctx, orderHashes)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if receipt != nil {
+			log.Printf("orders without the signed zone cancelled in block %s", receipt.BlockNumber)
+		}
+		return
+	}
+
+	orders := make([]pkg.Parameters, 0, len(orderHashes))
+	for _, hash := range orderHashes {
+		order, err := account.GetOrder(ctx, hash)
+		if err != nil {
+			log.Fatal(err)
+		}
+		orders = append(orders, order.ProtocolData.Parameters)
+	}
+	receipt, err := account.CancelOrders(ctx, orders)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%d orders cancelled in block %s", len(orders), receipt.BlockNumber)
+}
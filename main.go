@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -10,6 +11,23 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "run":
+			runCommand(os.Args[2:])
+			return
+		case "cancel":
+			cancelCommand(os.Args[2:])
+			return
+		case "orders":
+			ordersCommand(os.Args[2:])
+			return
+		case "weth":
+			wethCommand(os.Args[2:])
+			return
+		}
+	}
+
 	p := tea.NewProgram(initialModel())
 	if _, err := p.Run(); err != nil {
 		log.Fatal(err)
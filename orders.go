@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"opensea-bot/pkg"
+	"time"
+)
+
+func ordersCommand(args []string) {
+	fs := flag.NewFlagSet("orders", flag.ExitOnError)
+	storePath := fs.String("store", "orders.db", "path of the order store")
+	status := fs.String("status", "", "only list orders with this status")
+	_ = fs.Parse(args)
+
+	store, err := pkg.OpenOrderStore(*storePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer store.Close()
+
+	var statuses []pkg.OrderStatus
+	if *status != "" {
+		statuses = append(statuses, pkg.OrderStatus(*status))
+	}
+	orders, err := store.List(statuses...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, order := range orders {
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\texpires %s\n", order.OrderHash, order.Chain, order.Side, order.Status,
+			order.Price, time.Unix(order.ExpireAt, 0).Format(time.RFC3339))
+	}
+}
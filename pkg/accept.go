@@ -0,0 +1,187 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"log"
+	"math/big"
+	"opensea-bot/pkg/seaport"
+)
+
+var ErrOfferBelowMinPrice = errors.New("best offer is below the minimum price")
+
+func (a *Account) GetBestOfferByNFT(ctx context.Context, identifier string) (*BestOfferResp, error) {
+	var data *BestOfferResp
+	req := a.newRequest().
+		Get(fmt.Sprintf("%s/api/v2/offers/collection/%s/nfts/%s/best", getOpenSeaAPI(a.contract.Chain), a.contract.Collection, identifier))
+	log.Println(req.AsCurlCommand())
+	resp, _, errs := req.EndStruct(&data)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	log.Println(resp)
+	return data, nil
+}
+
+// AcceptBestOffer sells nft into its best offer, item, collection or trait,
+// when the proceeds after fees are at least minPrice.
+func (a *Account) AcceptBestOffer(ctx context.Context, nft *NFT, minPrice string) (*ethtypes.Receipt, error) {
+	threshold, err := parsePositive(minPrice)
+	if err != nil {
+		return nil, fmt.Errorf("min price: %w", err)
+	}
+
+	best, err := a.GetBestOfferByNFT(ctx, nft.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	if best == nil || best.OrderHash == "" {
+		return nil, fmt.Errorf("no offer for %s #%s", nft.Contract, nft.Identifier)
+	}
+
+	fulfillment, err := a.offerFulfillmentData(ctx, best, nft)
+	if err != nil {
+		return nil, err
+	}
+	if len(fulfillment.FulfillmentData.Orders) == 0 {
+		return nil, fmt.Errorf("no fulfillment order returned for %s", best.OrderHash)
+	}
+	order, err := fulfillment.advancedOrder(0)
+	if err != nil {
+		return nil, err
+	}
+
+	proceeds := offerProceeds(order).Shift(int32(-best.Price.Decimals))
+	if proceeds.LessThan(threshold) {
+		return nil, fmt.Errorf("%w: %s < %s", ErrOfferBelowMinPrice, proceeds, threshold)
+	}
+
+	address, err := fulfillment.seaportAddress(best.ProtocolAddress)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := seaport.NewSeaport(address, a.client)
+	if err != nil {
+		return nil, err
+	}
+
+	identifier, err := parseBigInt(nft.Identifier)
+	if err != nil {
+		return nil, fmt.Errorf("identifier: %w", err)
+	}
+	// the NFT sold moves through the conduit like a listed one
+	sold := OfferItem{
+		ItemType:             nft.nftType(),
+		Token:                nft.Contract,
+		IdentifierOrCriteria: identifier,
+		StartAmount:          big.NewInt(1),
+		EndAmount:            big.NewInt(1),
+	}
+	if err := a.ensureApprovals(ctx, []OfferItem{sold}); err != nil {
+		return nil, err
+	}
+
+	resolvers, err := fulfillment.criteriaResolvers()
+	if err != nil {
+		return nil, err
+	}
+	resolvers, err = resolveCriteria(order, resolvers, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := a.transactOpts(ctx, nil)
+	tx, err := instance.FulfillAdvancedOrder(opts, *order, resolvers,
+		hexStringToByte32(a.chain.ConduitKey), a.WalletAddress())
+	if err != nil {
+		return nil, err
+	}
+	return a.sendTx(ctx, fmt.Sprintf("accept offer %s for %s", best.OrderHash, nft.Identifier), tx)
+}
+
+func (a *Account) offerFulfillmentData(ctx context.Context, offer *BestOfferResp, nft *NFT) (*FulfillmentDataResp, error) {
+	protocolAddress := offer.ProtocolAddress
+	if protocolAddress == "" {
+		protocolAddress = a.chain.SeaportAddress
+	}
+	body := map[string]interface{}{
+		"offer": map[string]string{
+			"hash":             offer.OrderHash,
+			"chain":            a.contract.Chain,
+			"protocol_address": protocolAddress,
+		},
+		"fulfiller": map[string]string{
+			"address": a.WalletAddress().Hex(),
+		},
+		"consideration": map[string]string{
+			"asset_contract_address": nft.Contract,
+			"token_id":               nft.Identifier,
+		},
+	}
+
+	var data *FulfillmentDataResp
+	req := a.newRequest().
+		Post(fmt.Sprintf("%s/api/v2/offers/fulfillment_data", getOpenSeaAPI(a.contract.Chain))).Send(body)
+	log.Println(req.AsCurlCommand())
+	resp, _, errs := req.EndStruct(&data)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	log.Println(resp)
+	return data, nil
+}
+
+// resolveCriteria makes sure every criteria consideration item of order is
+// resolved to identifier. Items without a resolver from OpenSea can only be
+// resolved locally when they match any identifier (zero criteria root).
+func resolveCriteria(order *seaport.AdvancedOrder, resolvers []seaport.CriteriaResolver, identifier *big.Int) ([]seaport.CriteriaResolver, error) {
+	resolved := make(map[int64]bool)
+	for _, r := range resolvers {
+		if r.Side != 1 {
+			continue
+		}
+		if r.Identifier.Cmp(identifier) != 0 {
+			return nil, fmt.Errorf("criteria resolver targets identifier %s, expected %s", r.Identifier, identifier)
+		}
+		resolved[r.Index.Int64()] = true
+	}
+
+	for i, item := range order.Parameters.Consideration {
+		if (item.ItemType != 4 && item.ItemType != 5) || resolved[int64(i)] {
+			continue
+		}
+		if item.IdentifierOrCriteria.Sign() != 0 {
+			return nil, fmt.Errorf("missing criteria proof for consideration item %d", i)
+		}
+		resolvers = append(resolvers, seaport.CriteriaResolver{
+			OrderIndex:    big.NewInt(0),
+			Side:          1, // consideration
+			Index:         big.NewInt(int64(i)),
+			Identifier:    identifier,
+			CriteriaProof: [][32]byte{},
+		})
+	}
+	return resolvers, nil
+}
+
+// offerProceeds is the amount of ERC20 the fulfiller keeps: the offered tokens
+// minus the ERC20 consideration (fees), scaled to the fill fraction.
+func offerProceeds(order *seaport.AdvancedOrder) decimal.Decimal {
+	total := big.NewInt(0)
+	for _, item := range order.Parameters.Offer {
+		if item.ItemType == 1 {
+			total.Add(total, item.StartAmount)
+		}
+	}
+	for _, item := range order.Parameters.Consideration {
+		if item.ItemType == 1 && item.Recipient != order.Parameters.Offerer {
+			total.Sub(total, item.StartAmount)
+		}
+	}
+	return decimal.NewFromBigInt(total, 0).
+		Mul(decimal.NewFromBigInt(order.Numerator, 0)).
+		Div(decimal.NewFromBigInt(order.Denominator, 0))
+}
@@ -0,0 +1,113 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"opensea-bot/pkg/seaport"
+	"strings"
+	"testing"
+)
+
+func criteriaOrder(root *big.Int) *seaport.AdvancedOrder {
+	offerer := common.HexToAddress("0x9d1e5c9ba1c7b8fb8a4fa3e6d1da6e8a25e4b4a2")
+	weth := common.HexToAddress("0x7b79995e5f793A07Bc00c21412e50Ecae098E7f9")
+	return &seaport.AdvancedOrder{
+		Numerator:   big.NewInt(1),
+		Denominator: big.NewInt(2),
+		Parameters: seaport.OrderParameters{
+			Offerer: offerer,
+			Offer: []seaport.OfferItem{
+				{ItemType: 1, Token: weth, StartAmount: big.NewInt(2000), EndAmount: big.NewInt(2000)},
+			},
+			Consideration: []seaport.ConsiderationItem{
+				{ItemType: 4, IdentifierOrCriteria: root, StartAmount: big.NewInt(2), EndAmount: big.NewInt(2), Recipient: offerer},
+				{ItemType: 1, Token: weth, StartAmount: big.NewInt(50), EndAmount: big.NewInt(50), Recipient: common.HexToAddress("0x0000a26b00c1f0df003000390027140000faa719")},
+			},
+		},
+	}
+}
+
+func TestResolveCriteria(t *testing.T) {
+	resolvers, err := resolveCriteria(criteriaOrder(big.NewInt(0)), nil, big.NewInt(42))
+	require.Nil(t, err)
+	require.Len(t, resolvers, 1)
+	require.Equal(t, uint8(1), resolvers[0].Side)
+	require.Equal(t, int64(0), resolvers[0].Index.Int64())
+	require.Equal(t, int64(42), resolvers[0].Identifier.Int64())
+
+	_, err = resolveCriteria(criteriaOrder(big.NewInt(7)), nil, big.NewInt(42))
+	require.NotNil(t, err)
+
+	proof := []seaport.CriteriaResolver{{OrderIndex: big.NewInt(0), Side: 1, Index: big.NewInt(0), Identifier: big.NewInt(42)}}
+	resolvers, err = resolveCriteria(criteriaOrder(big.NewInt(7)), proof, big.NewInt(42))
+	require.Nil(t, err)
+	require.Len(t, resolvers, 1)
+
+	proof[0].Identifier = big.NewInt(43)
+	_, err = resolveCriteria(criteriaOrder(big.NewInt(7)), proof, big.NewInt(42))
+	require.NotNil(t, err)
+}
+
+func TestOfferProceeds(t *testing.T) {
+	require.Equal(t, "975", offerProceeds(criteriaOrder(big.NewInt(0))).String())
+}
+
+const offerFulfillmentFixture = `{
+	"protocol": "seaport1.6",
+	"fulfillment_data": {
+		"transaction": {"to": "0x0000000000000068f116a894984e2db1123eb395"},
+		"orders": [{
+			"parameters": {
+				"offerer": "0x9d1e5c9ba1c7b8fb8a4fa3e6d1da6e8a25e4b4a2",
+				"offer": [{"itemType": 1, "token": "0x7b79995e5f793A07Bc00c21412e50Ecae098E7f9", "identifierOrCriteria": "0", "startAmount": "2000000000000000000", "endAmount": "2000000000000000000"}],
+				"consideration": [
+					{"itemType": 2, "token": "0x300b105942d6d181cdfe8199fd48eb09d26efd24", "identifierOrCriteria": "7", "startAmount": "1", "endAmount": "1", "recipient": "0x9d1e5c9ba1c7b8fb8a4fa3e6d1da6e8a25e4b4a2"},
+					{"itemType": 1, "token": "0x7b79995e5f793A07Bc00c21412e50Ecae098E7f9", "identifierOrCriteria": "0", "startAmount": "50000000000000000", "endAmount": "50000000000000000", "recipient": "0x0000a26b00c1f0df003000390027140000faa719"}
+				],
+				"startTime": "1700000000",
+				"endTime": "1700003600",
+				"orderType": 0,
+				"zone": "0x0000000000000000000000000000000000000000",
+				"zoneHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+				"salt": "0x01",
+				"conduitKey": "0x0000007b02230091a7ed01230072f7006a004d60a8d4e71d599b8104250f0000",
+				"totalOriginalConsiderationItems": 2
+			},
+			"signature": "0xabcd"
+		}]
+	}
+}`
+
+func TestAcceptBestOffer_FulfillsOnOrderSeaport(t *testing.T) {
+	fixture := offerFulfillmentFixture
+	client := apiClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"order_hash": "0x01", "protocol_address": "` + SeaportV16Address + `",
+				"price": {"currency": "WETH", "decimals": 18, "value": "2000000000000000000"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(fixture))
+	})
+	backend := newApprovalBackend(t)
+	account := approvalAccount(t, backend)
+	var err error
+	account.http, err = httpAgent(client)
+	require.Nil(t, err)
+	account.SetApprovalConfirm(AutoApprove)
+	nft := &NFT{Identifier: "7", Contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", TokenStandard: NftType721}
+
+	_, err = account.AcceptBestOffer(context.Background(), nft, "1")
+	require.Nil(t, err)
+	// the conduit approval, then the fulfillment
+	require.Len(t, backend.sent, 2)
+	require.Equal(t, common.HexToAddress("0x0000000000000068f116a894984e2db1123eb395"), *backend.sent[1].To())
+
+	fixture = strings.Replace(offerFulfillmentFixture, SeaportV16Address, testContract, 1)
+	_, err = account.AcceptBestOffer(context.Background(), nft, "1")
+	require.True(t, errors.Is(err, ErrUnknownSeaport))
+	require.Len(t, backend.sent, 2)
+}
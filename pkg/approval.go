@@ -0,0 +1,232 @@
+package pkg
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/term"
+	"log"
+	"math/big"
+	"opensea-bot/pkg/conduit"
+	"opensea-bot/pkg/erc20"
+	"opensea-bot/pkg/erc721"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrNotApproved is returned when an order needs an approval the wallet has not
+// given and the confirm function declined to send.
+var ErrNotApproved = errors.New("conduit not approved")
+
+// Approval is a permission the Seaport conduit lacks to fill an order: an
+// operator approval for an NFT contract, or an ERC-20 allowance when Amount is
+// set.
+type Approval struct {
+	Token     common.Address
+	Conduit   common.Address
+	Amount    *big.Int
+	Allowance *big.Int
+}
+
+func (ap Approval) String() string {
+	if ap.Amount == nil {
+		return fmt.Sprintf("setApprovalForAll(%s) on %s", ap.Conduit.Hex(), ap.Token.Hex())
+	}
+	return fmt.Sprintf("approve(%s) on %s, allowance %s is below %s", ap.Conduit.Hex(), ap.Token.Hex(), ap.Allowance, ap.Amount)
+}
+
+// ConfirmFunc decides whether a missing approval is sent.
+type ConfirmFunc func(Approval) bool
+
+// AutoApprove sends every approval without asking.
+func AutoApprove(Approval) bool {
+	return true
+}
+
+var promptMu sync.Mutex
+
+// PromptApproval asks on the terminal before sending an approval, it declines
+// when stdin is not a terminal.
+func PromptApproval(ap Approval) bool {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		log.Printf("cannot confirm %s without a terminal", ap)
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "send %s? [y/N] ", ap)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// Conduit returns the address the chain's conduit key resolves to in the
+// Seaport conduit controller, Seaport itself for the zero key. It is the
+// operator orders need approved.
+func (a *Account) Conduit(ctx context.Context) (common.Address, error) {
+	if a.conduit != zeroAddress() {
+		return a.conduit, nil
+	}
+	key := hexStringToByte32(a.chain.ConduitKey)
+	if key == [32]byte{} {
+		a.conduit = common.HexToAddress(a.chain.SeaportAddress)
+		return a.conduit, nil
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	info, err := a.seaportInstance.Information(opts)
+	if err != nil {
+		return zeroAddress(), err
+	}
+	controller, err := conduit.NewConduitControllerCaller(info.ConduitController, a.client)
+	if err != nil {
+		return zeroAddress(), err
+	}
+	resolved, err := controller.GetConduit(opts, key)
+	if err != nil {
+		return zeroAddress(), err
+	}
+	if !resolved.Exists {
+		return zeroAddress(), fmt.Errorf("no conduit for key %s", a.chain.ConduitKey)
+	}
+	a.conduit = resolved.Conduit
+	return a.conduit, nil
+}
+
+// NFTApproved reports whether the conduit may move the wallet's tokens of an
+// ERC-721 or ERC-1155 contract.
+func (a *Account) NFTApproved(ctx context.Context, contract common.Address) (bool, error) {
+	operator, err := a.Conduit(ctx)
+	if err != nil {
+		return false, err
+	}
+	token, err := erc721.NewERC721Caller(contract, a.client)
+	if err != nil {
+		return false, err
+	}
+	return token.IsApprovedForAll(&bind.CallOpts{Context: ctx}, a.WalletAddress(), operator)
+}
+
+// TokenAllowance returns how much of an ERC-20 token the conduit may spend
+// from the wallet.
+func (a *Account) TokenAllowance(ctx context.Context, token common.Address) (*big.Int, error) {
+	spender, err := a.Conduit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	erc20Token, err := erc20.NewERC20Caller(token, a.client)
+	if err != nil {
+		return nil, err
+	}
+	return erc20Token.Allowance(&bind.CallOpts{Context: ctx}, a.WalletAddress(), spender)
+}
+
+// MissingApprovals returns the approvals the wallet lacks for the conduit to
+// transfer the offer items of an order. Native currency needs none.
+func (a *Account) MissingApprovals(ctx context.Context, items []OfferItem) ([]Approval, error) {
+	operator, err := a.Conduit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	amounts := map[common.Address]*big.Int{}
+	var tokens []common.Address
+	for _, item := range items {
+		token := common.HexToAddress(item.Token)
+		switch item.ItemType {
+		case 0: // NATIVE
+		case 1: // ERC20
+			amount := maxAmount(item)
+			if total, ok := amounts[token]; ok {
+				total.Add(total, amount)
+			} else {
+				amounts[token] = new(big.Int).Set(amount)
+				tokens = append(tokens, token)
+			}
+		default: // ERC721, ERC1155 and their criteria
+			if _, ok := amounts[token]; !ok {
+				amounts[token] = nil
+				tokens = append(tokens, token)
+			}
+		}
+	}
+
+	var missing []Approval
+	for _, token := range tokens {
+		if amount := amounts[token]; amount != nil {
+			allowance, err := a.TokenAllowance(ctx, token)
+			if err != nil {
+				return nil, err
+			}
+			if allowance.Cmp(amount) < 0 {
+				missing = append(missing, Approval{Token: token, Conduit: operator, Amount: amount, Allowance: allowance})
+			}
+			continue
+		}
+		approved, err := a.NFTApproved(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if !approved {
+			missing = append(missing, Approval{Token: token, Conduit: operator})
+		}
+	}
+	return missing, nil
+}
+
+// Approve sends the approval transaction. ERC-20 allowances are set to the
+// maximum so later orders don't need another one.
+func (a *Account) Approve(ctx context.Context, ap Approval) (*ethtypes.Receipt, error) {
+	tx, err := a.approvalTx(ctx, ap)
+	if err != nil {
+		return nil, err
+	}
+	return a.sendTx(ctx, "approve "+ap.Token.Hex(), tx)
+}
+
+func (a *Account) approvalTx(ctx context.Context, ap Approval) (*ethtypes.Transaction, error) {
+	if ap.Amount == nil {
+		token, err := erc721.NewERC721Transactor(ap.Token, a.client)
+		if err != nil {
+			return nil, err
+		}
+		return token.SetApprovalForAll(a.transactOpts(ctx, nil), ap.Conduit, true)
+	}
+	token, err := erc20.NewERC20Transactor(ap.Token, a.client)
+	if err != nil {
+		return nil, err
+	}
+	return token.Approve(a.transactOpts(ctx, nil), ap.Conduit, math.MaxBig256)
+}
+
+// SetApprovalConfirm sets the function asked before an order's missing
+// approvals are sent. Without one orders needing an approval fail with
+// ErrNotApproved.
+func (a *Account) SetApprovalConfirm(confirm ConfirmFunc) {
+	a.confirmApproval = confirm
+}
+
+// ensureApprovals sends the approvals an order's offer items need, each one
+// confirmed first.
+func (a *Account) ensureApprovals(ctx context.Context, items []OfferItem) error {
+	missing, err := a.MissingApprovals(ctx, items)
+	if err != nil {
+		return fmt.Errorf("check approvals: %w", err)
+	}
+	for _, ap := range missing {
+		if a.confirmApproval == nil || !a.confirmApproval(ap) {
+			return fmt.Errorf("%w: %s", ErrNotApproved, ap)
+		}
+		if _, err := a.Approve(ctx, ap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
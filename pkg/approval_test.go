@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"opensea-bot/pkg/conduit"
+	"opensea-bot/pkg/erc20"
+	"opensea-bot/pkg/erc721"
+	"opensea-bot/pkg/seaport"
+	"testing"
+)
+
+var (
+	testController = common.HexToAddress("0x00000000F9490004C11Cef243f5400493c00Ad63")
+	testWETH       = common.HexToAddress("0x7b79995e5f793A07Bc00c21412e50Ecae098E7f9")
+)
+
+// approvalBackend answers the Seaport, conduit controller and token calls of
+// the approval checks, the wallet has no operator approval and an allowance
+// of 2. Its native and token balances are balance.
+type approvalBackend struct {
+	sendingBackend
+	t       *testing.T
+	calls   map[string]int
+	balance *big.Int
+}
+
+func newApprovalBackend(t *testing.T) *approvalBackend {
+	return &approvalBackend{t: t, calls: map[string]int{}, balance: new(big.Int)}
+}
+
+func (b *approvalBackend) BalanceAt(context.Context, common.Address, *big.Int) (*big.Int, error) {
+	return b.balance, nil
+}
+
+func (b *approvalBackend) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	for _, metaData := range []*bind.MetaData{seaport.SeaportMetaData, conduit.ConduitControllerMetaData, erc721.ERC721MetaData, erc20.ERC20MetaData} {
+		parsed, err := metaData.GetAbi()
+		require.Nil(b.t, err)
+		method, err := parsed.MethodById(msg.Data[:4])
+		if err != nil {
+			continue
+		}
+		b.calls[method.Name]++
+		return method.Outputs.Pack(b.result(method)...)
+	}
+	return nil, errors.New("unexpected call")
+}
+
+func (b *approvalBackend) result(method *abi.Method) []interface{} {
+	switch method.Name {
+	case "information":
+		return []interface{}{"1.6", [32]byte{}, testController}
+	case "getConduit":
+		return []interface{}{common.HexToAddress(OpenSeaConduitAddress), true}
+	case "isApprovedForAll":
+		return []interface{}{false}
+	case "allowance":
+		return []interface{}{big.NewInt(2)}
+	case "balanceOf":
+		return []interface{}{b.balance}
+	case "getOrderStatus":
+		return []interface{}{false, false, new(big.Int), new(big.Int)}
+	}
+	b.t.Fatalf("unexpected method %s", method.Name)
+	return nil
+}
+
+func approvalAccount(t *testing.T, backend *approvalBackend) *Account {
+	account := testAccount(t, backend, nil)
+	var err error
+	account.seaportInstance, err = seaport.NewSeaport(common.HexToAddress(account.chain.SeaportAddress), backend)
+	require.Nil(t, err)
+	return account
+}
+
+func TestAccount_Approvals(t *testing.T) {
+	backend := newApprovalBackend(t)
+	account := approvalAccount(t, backend)
+	ctx := context.Background()
+
+	operator, err := account.Conduit(ctx)
+	require.Nil(t, err)
+	require.Equal(t, common.HexToAddress(OpenSeaConduitAddress), operator)
+
+	nft := common.HexToAddress(testContract)
+	items := []OfferItem{
+		{ItemType: 2, Token: nft.Hex(), StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)},
+		{ItemType: 1, Token: testWETH.Hex(), StartAmount: big.NewInt(5), EndAmount: big.NewInt(5)},
+		{ItemType: 1, Token: testWETH.Hex(), StartAmount: big.NewInt(3), EndAmount: big.NewInt(1)},
+		{ItemType: 0, Token: zeroAddress().Hex(), StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)},
+	}
+	missing, err := account.MissingApprovals(ctx, items)
+	require.Nil(t, err)
+	require.Equal(t, []Approval{
+		{Token: nft, Conduit: operator},
+		{Token: testWETH, Conduit: operator, Amount: big.NewInt(8), Allowance: big.NewInt(2)},
+	}, missing)
+	// the conduit is resolved once
+	require.Equal(t, 1, backend.calls["getConduit"])
+
+	require.True(t, errors.Is(account.ensureApprovals(ctx, items), ErrNotApproved))
+	require.Empty(t, backend.sent)
+
+	account.SetApprovalConfirm(AutoApprove)
+	require.Nil(t, account.ensureApprovals(ctx, items))
+	require.Len(t, backend.sent, 2)
+
+	erc721ABI, err := erc721.ERC721MetaData.GetAbi()
+	require.Nil(t, err)
+	approveAll, err := erc721ABI.Pack("setApprovalForAll", operator, true)
+	require.Nil(t, err)
+	require.Equal(t, nft, *backend.sent[0].To())
+	require.Equal(t, approveAll, backend.sent[0].Data())
+
+	erc20ABI, err := erc20.ERC20MetaData.GetAbi()
+	require.Nil(t, err)
+	approve, err := erc20ABI.Pack("approve", operator, math.MaxBig256)
+	require.Nil(t, err)
+	require.Equal(t, testWETH, *backend.sent[1].To())
+	require.Equal(t, approve, backend.sent[1].Data())
+}
@@ -0,0 +1,334 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"log"
+	"math/big"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// Backend is the node access an Account needs. It is implemented by
+// *ethclient.Client, by RPCPool and by go-ethereum's simulated backend.
+type Backend interface {
+	bind.ContractBackend
+	bind.PendingContractCaller
+	bind.DeployBackend
+	ethereum.ChainIDReader
+	ethereum.BlockNumberReader
+	ethereum.ChainStateReader
+}
+
+var ErrNoHealthyEndpoint = errors.New("no healthy rpc endpoint")
+
+// maxHeadLag is how many blocks an endpoint may trail the best one before the
+// health check takes it out of rotation.
+const maxHeadLag = 5
+
+const healthCheckTimeout = 5 * time.Second
+
+// RPCPool spreads calls over several RPC endpoints of one chain, HTTP,
+// WebSocket or IPC. Calls go to the first healthy endpoint and fail over to the
+// next one when an endpoint cannot be reached. Errors returned by a node, such
+// as reverts, are not retried.
+type RPCPool struct {
+	chainID *big.Int
+
+	mu        sync.Mutex
+	endpoints []*rpcEndpoint
+}
+
+type rpcEndpoint struct {
+	url     string
+	client  *ethclient.Client
+	healthy bool
+	err     error
+}
+
+// DialChain connects to the RPC endpoints given, or to the registry's endpoint
+// of chain when there are none, and checks they serve the chain. Endpoints are
+// expanded with os.ExpandEnv like Chain.RPCURL.
+func DialChain(ctx context.Context, chain *Chain, urls ...string) (*RPCPool, error) {
+	endpoints := []string{chain.rpcURL()}
+	if len(urls) > 0 {
+		endpoints = make([]string, len(urls))
+		for i, u := range urls {
+			endpoints[i] = os.ExpandEnv(u)
+		}
+	}
+	return DialRPCPool(ctx, big.NewInt(chain.ChainID), endpoints...)
+}
+
+// DialRPCPool connects to urls. Endpoints serving another chain than chainID,
+// when set, are marked unhealthy. It fails when no endpoint is usable.
+func DialRPCPool(ctx context.Context, chainID *big.Int, urls ...string) (*RPCPool, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no rpc endpoint configured")
+	}
+	p := &RPCPool{chainID: chainID}
+	for _, u := range urls {
+		p.endpoints = append(p.endpoints, &rpcEndpoint{url: u})
+	}
+	if err := p.CheckHealth(ctx); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// CheckHealth probes every endpoint, redialing the ones that are down. An
+// endpoint is healthy when it serves the expected chain and its head is within
+// maxHeadLag blocks of the best endpoint.
+func (p *RPCPool) CheckHealth(ctx context.Context) error {
+	p.mu.Lock()
+	endpoints := append([]*rpcEndpoint{}, p.endpoints...)
+	p.mu.Unlock()
+
+	heads := make([]uint64, len(endpoints))
+	var best uint64
+	for i, e := range endpoints {
+		head, err := p.probe(ctx, e)
+		p.mu.Lock()
+		e.healthy, e.err = err == nil, err
+		p.mu.Unlock()
+		if err != nil {
+			log.Printf("rpc %s unhealthy: %v", redactURL(e.url), err)
+			continue
+		}
+		heads[i] = head
+		if head > best {
+			best = head
+		}
+	}
+
+	healthy := 0
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, e := range endpoints {
+		if e.healthy && best-heads[i] > maxHeadLag {
+			e.healthy, e.err = false, fmt.Errorf("head %d is %d blocks behind", heads[i], best-heads[i])
+			log.Printf("rpc %s unhealthy: %v", redactURL(e.url), e.err)
+		}
+		if e.healthy {
+			healthy++
+		}
+	}
+	if healthy == 0 {
+		return ErrNoHealthyEndpoint
+	}
+	return nil
+}
+
+func (p *RPCPool) probe(ctx context.Context, e *rpcEndpoint) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	p.mu.Lock()
+	client := e.client
+	p.mu.Unlock()
+	if client == nil {
+		var err error
+		if client, err = ethclient.DialContext(ctx, e.url); err != nil {
+			return 0, err
+		}
+		p.mu.Lock()
+		e.client = client
+		p.mu.Unlock()
+	}
+
+	if p.chainID != nil {
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if chainID.Cmp(p.chainID) != 0 {
+			return 0, fmt.Errorf("serves chain id %s, expected %s", chainID, p.chainID)
+		}
+	}
+	return client.BlockNumber(ctx)
+}
+
+// StartHealthChecks runs CheckHealth every interval until ctx is done.
+func (p *RPCPool) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := p.CheckHealth(ctx); err != nil {
+					log.Printf("rpc health check: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (p *RPCPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range p.endpoints {
+		if e.client != nil {
+			e.client.Close()
+			e.client = nil
+		}
+		e.healthy = false
+	}
+}
+
+// candidates returns the dialed endpoints, healthy ones first, so a call still
+// goes through when every endpoint was marked down.
+func (p *RPCPool) candidates() []*rpcEndpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	healthy := make([]*rpcEndpoint, 0, len(p.endpoints))
+	down := make([]*rpcEndpoint, 0)
+	for _, e := range p.endpoints {
+		switch {
+		case e.client == nil:
+		case e.healthy:
+			healthy = append(healthy, e)
+		default:
+			down = append(down, e)
+		}
+	}
+	return append(healthy, down...)
+}
+
+func (p *RPCPool) markDown(e *rpcEndpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e.healthy, e.err = false, err
+}
+
+func poolCall[T any](ctx context.Context, p *RPCPool, fn func(*ethclient.Client) (T, error)) (T, error) {
+	var zero T
+	lastErr := ErrNoHealthyEndpoint
+	for _, e := range p.candidates() {
+		value, err := fn(e.client)
+		if err == nil || !endpointFailed(ctx, err) {
+			return value, err
+		}
+		lastErr = err
+		if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			// an HTTP endpoint, fine for anything but subscriptions
+			continue
+		}
+		log.Printf("rpc %s failed, failing over: %v", redactURL(e.url), err)
+		p.markDown(e, err)
+	}
+	return zero, lastErr
+}
+
+// endpointFailed tells errors of the endpoint itself, worth retrying on another
+// one, from answers of the node.
+func endpointFailed(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+// redactURL keeps the scheme and host of an endpoint, its path and query often
+// carry an API key.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+func (p *RPCPool) ChainID(ctx context.Context) (*big.Int, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.ChainID(ctx) })
+}
+
+func (p *RPCPool) BlockNumber(ctx context.Context) (uint64, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.BlockNumber(ctx) })
+}
+
+func (p *RPCPool) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) ([]byte, error) { return c.CodeAt(ctx, contract, blockNumber) })
+}
+
+func (p *RPCPool) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.BalanceAt(ctx, account, blockNumber) })
+}
+
+func (p *RPCPool) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) ([]byte, error) { return c.StorageAt(ctx, account, key, blockNumber) })
+}
+
+func (p *RPCPool) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.NonceAt(ctx, account, blockNumber) })
+}
+
+func (p *RPCPool) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) ([]byte, error) { return c.CallContract(ctx, call, blockNumber) })
+}
+
+func (p *RPCPool) HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*ethtypes.Header, error) { return c.HeaderByNumber(ctx, number) })
+}
+
+func (p *RPCPool) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) ([]byte, error) { return c.PendingCodeAt(ctx, account) })
+}
+
+func (p *RPCPool) PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) ([]byte, error) { return c.PendingCallContract(ctx, call) })
+}
+
+func (p *RPCPool) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.PendingNonceAt(ctx, account) })
+}
+
+func (p *RPCPool) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasPrice(ctx) })
+}
+
+func (p *RPCPool) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasTipCap(ctx) })
+}
+
+func (p *RPCPool) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.EstimateGas(ctx, call) })
+}
+
+func (p *RPCPool) SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error {
+	_, err := poolCall(ctx, p, func(c *ethclient.Client) (struct{}, error) { return struct{}{}, c.SendTransaction(ctx, tx) })
+	return err
+}
+
+func (p *RPCPool) TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethtypes.Receipt, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (*ethtypes.Receipt, error) { return c.TransactionReceipt(ctx, txHash) })
+}
+
+func (p *RPCPool) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]ethtypes.Log, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) ([]ethtypes.Log, error) { return c.FilterLogs(ctx, query) })
+}
+
+// SubscribeFilterLogs needs a WebSocket or IPC endpoint, HTTP endpoints are
+// skipped.
+func (p *RPCPool) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- ethtypes.Log) (ethereum.Subscription, error) {
+	return poolCall(ctx, p, func(c *ethclient.Client) (ethereum.Subscription, error) {
+		return c.SubscribeFilterLogs(ctx, query, ch)
+	})
+}
@@ -0,0 +1,122 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+var _ Backend = (*RPCPool)(nil)
+var _ Backend = (*ethclient.Client)(nil)
+
+// fakeNode answers eth_chainId and eth_blockNumber, or 503 once down is set.
+type fakeNode struct {
+	chainID int64
+	head    atomic.Uint64
+	down    atomic.Bool
+	calls   atomic.Int64
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.calls.Add(1)
+	if n.down.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	var result string
+	switch req.Method {
+	case "eth_chainId":
+		result = fmt.Sprintf("0x%x", n.chainID)
+	case "eth_blockNumber":
+		result = fmt.Sprintf("0x%x", n.head.Load())
+	default:
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID,
+			"error": map[string]interface{}{"code": -32601, "message": "method not found"}})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+}
+
+func startFakeNode(t *testing.T, chainID int64, head uint64) (*fakeNode, string) {
+	node := &fakeNode{chainID: chainID}
+	node.head.Store(head)
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	return node, server.URL
+}
+
+func TestRPCPoolFailover(t *testing.T) {
+	ctx := context.Background()
+	first, firstURL := startFakeNode(t, 1, 100)
+	second, secondURL := startFakeNode(t, 1, 101)
+	pool, err := DialRPCPool(ctx, big.NewInt(1), firstURL, secondURL)
+	require.Nil(t, err)
+	defer pool.Close()
+
+	head, err := pool.BlockNumber(ctx)
+	require.Nil(t, err)
+	require.Equal(t, uint64(100), head)
+
+	first.down.Store(true)
+	head, err = pool.BlockNumber(ctx)
+	require.Nil(t, err)
+	require.Equal(t, uint64(101), head)
+
+	// the failed endpoint is skipped until a health check brings it back
+	calls := first.calls.Load()
+	_, err = pool.BlockNumber(ctx)
+	require.Nil(t, err)
+	require.Equal(t, calls, first.calls.Load())
+
+	first.down.Store(false)
+	require.Nil(t, pool.CheckHealth(ctx))
+	head, err = pool.BlockNumber(ctx)
+	require.Nil(t, err)
+	require.Equal(t, uint64(100), head)
+
+	// errors answered by the node are not retried elsewhere
+	calls = second.calls.Load()
+	_, err = pool.SuggestGasPrice(ctx)
+	require.NotNil(t, err)
+	require.Equal(t, calls, second.calls.Load())
+}
+
+func TestRPCPoolHealth(t *testing.T) {
+	ctx := context.Background()
+	_, wrongChain := startFakeNode(t, 5, 100)
+	_, err := DialRPCPool(ctx, big.NewInt(1), wrongChain)
+	require.ErrorIs(t, err, ErrNoHealthyEndpoint)
+
+	_, err = DialRPCPool(ctx, big.NewInt(1))
+	require.NotNil(t, err)
+
+	lagging, laggingURL := startFakeNode(t, 1, 90)
+	_, syncedURL := startFakeNode(t, 1, 100)
+	pool, err := DialRPCPool(ctx, big.NewInt(1), laggingURL, syncedURL)
+	require.Nil(t, err)
+	defer pool.Close()
+
+	calls := lagging.calls.Load()
+	head, err := pool.BlockNumber(ctx)
+	require.Nil(t, err)
+	require.Equal(t, uint64(100), head)
+	require.Equal(t, calls, lagging.calls.Load())
+}
+
+func TestRedactURL(t *testing.T) {
+	require.Equal(t, "https://mainnet.infura.io", redactURL("https://mainnet.infura.io/v3/secret"))
+	require.Equal(t, "wss://node.example", redactURL("wss://node.example/ws?key=secret"))
+	require.Equal(t, "/var/run/geth.ipc", redactURL("/var/run/geth.ipc"))
+}
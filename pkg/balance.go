@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"opensea-bot/pkg/erc1155"
+	"opensea-bot/pkg/erc20"
+	"strings"
+)
+
+// fillBalances sets the balance of the wallet on each of nfts, reading the
+// ERC-1155 ones with one balanceOfBatch call per contract.
+func (a *Account) fillBalances(ctx context.Context, nfts []NFT) error {
+	byContract := make(map[string][]int)
+	for i := range nfts {
+		if nfts[i].nftType() != 3 {
+			nfts[i].Balance = big.NewInt(1)
+			continue
+		}
+		contract := strings.ToLower(nfts[i].Contract)
+		byContract[contract] = append(byContract[contract], i)
+	}
+
+	for contract, indexes := range byContract {
+		token, err := erc1155.NewERC1155Caller(common.HexToAddress(contract), a.client)
+		if err != nil {
+			return err
+		}
+		owners := make([]common.Address, 0, len(indexes))
+		ids := make([]*big.Int, 0, len(indexes))
+		for _, i := range indexes {
+			id, err := nfts[i].identifier()
+			if err != nil {
+				return err
+			}
+			owners = append(owners, a.WalletAddress())
+			ids = append(ids, id)
+		}
+		balances, err := token.BalanceOfBatch(&bind.CallOpts{Context: ctx}, owners, ids)
+		if err != nil {
+			return err
+		}
+		for j, i := range indexes {
+			nfts[i].Balance = balances[j]
+		}
+	}
+	return nil
+}
+
+// TokenBalance is the wallet's balance of a payment token, Amount in the
+// token's smallest unit.
+type TokenBalance struct {
+	Symbol   string
+	Token    common.Address
+	Decimals int
+	Amount   *big.Int
+}
+
+func (b *TokenBalance) String() string {
+	return decimal.NewFromBigInt(b.Amount, -int32(b.Decimals)).String() + " " + b.Symbol
+}
+
+// Balance returns the wallet's balance of the payment token at address, the
+// chain's default listing currency when address is empty. The zero address is
+// the native token.
+func (a *Account) Balance(ctx context.Context, address string) (*TokenBalance, error) {
+	token, err := a.paymentToken(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || !common.IsHexAddress(token.Address) {
+		return nil, fmt.Errorf("unknown payment token %q", address)
+	}
+	balance := &TokenBalance{Symbol: token.Symbol, Token: common.HexToAddress(token.Address), Decimals: token.Decimals}
+	balance.Amount, err = a.TokenBalance(ctx, balance.Token)
+	if err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+// TokenBalance returns the wallet's balance of an ERC-20 token in its smallest
+// unit, or of the native token for the zero address.
+func (a *Account) TokenBalance(ctx context.Context, token common.Address) (*big.Int, error) {
+	if token == zeroAddress() {
+		return a.client.BalanceAt(ctx, a.WalletAddress(), nil)
+	}
+	erc20Token, err := erc20.NewERC20Caller(token, a.client)
+	if err != nil {
+		return nil, err
+	}
+	return erc20Token.BalanceOf(&bind.CallOpts{Context: ctx}, a.WalletAddress())
+}
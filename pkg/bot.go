@@ -0,0 +1,468 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"log"
+	"strings"
+	"time"
+)
+
+const healthCheckInterval = 30 * time.Second
+
+type Bot struct {
+	config *Config
+	dryRun bool
+}
+
+func NewBot(config *Config, dryRun bool) *Bot {
+	return &Bot{
+		config: config,
+		dryRun: dryRun,
+	}
+}
+
+// Run executes every rule of the configuration once. When some collection is
+// repriced it then keeps repricing until ctx is cancelled.
+func (b *Bot) Run(ctx context.Context) error {
+	var store *OrderStore
+	if b.config.Store != "" {
+		var err error
+		if store, err = OpenOrderStore(b.config.Store); err != nil {
+			return err
+		}
+		defer store.Close()
+	}
+
+	signers, closeSigners, err := b.openSigners(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeSigners()
+
+	pools := map[string]*RPCPool{}
+	defer func() {
+		for _, pool := range pools {
+			pool.Close()
+		}
+	}()
+
+	// accounts of one wallet on one chain share nonces
+	txManagers := map[string]*TxManager{}
+
+	wallets := make([]*AccountManager, len(b.config.Collections))
+	for i := range b.config.Collections {
+		col := &b.config.Collections[i]
+		pool, ok := pools[col.Chain]
+		if !ok {
+			network, err := LookupChain(col.Chain)
+			if err != nil {
+				return err
+			}
+			if pool, err = DialChain(ctx, network, b.config.RPC[network.Name]...); err != nil {
+				return fmt.Errorf("%s: %w", network.Name, err)
+			}
+			pool.StartHealthChecks(ctx, healthCheckInterval)
+			pools[col.Chain] = pool
+		}
+		manager, err := b.accounts(ctx, col, pool, signers, store, txManagers)
+		if err != nil {
+			return err
+		}
+		wallets[i] = manager
+
+		if col.Sell != nil {
+			for _, account := range manager.Accounts() {
+				if err := b.sell(ctx, account, col); err != nil {
+					return err
+				}
+			}
+		}
+		if col.Buy != nil {
+			if err := b.buy(ctx, manager, col); err != nil {
+				return err
+			}
+		}
+		if col.Offer != nil {
+			b.offer(ctx, manager, col)
+		}
+	}
+
+	interval := b.config.repriceInterval()
+	if interval == 0 {
+		return nil
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		for i := range b.config.Collections {
+			col := &b.config.Collections[i]
+			if col.Sell == nil || col.Sell.Reprice == nil {
+				continue
+			}
+			for _, account := range wallets[i].Accounts() {
+				if err := b.repriceAll(ctx, account, col); err != nil {
+					log.Printf("reprice %s from %s: %v", col.Contract, account.WalletAddress().Hex(), err)
+				}
+			}
+		}
+	}
+}
+
+// openSigners opens the configured wallets. A nil signer stands for the
+// PRIVATE_KEY wallet used when none is configured.
+func (b *Bot) openSigners(ctx context.Context) ([]Signer, func(), error) {
+	configs := b.config.walletConfigs()
+	if len(configs) == 0 {
+		return []Signer{nil}, func() {}, nil
+	}
+	signers := make([]Signer, 0, len(configs))
+	closeSigners := func() {
+		for _, signer := range signers {
+			if external, ok := signer.(*ExternalSigner); ok {
+				external.Close()
+			}
+		}
+	}
+	for i, config := range configs {
+		signer, err := OpenSigner(ctx, config)
+		if err != nil {
+			closeSigners()
+			return nil, nil, fmt.Errorf("wallets[%d]: %w", i, err)
+		}
+		signers = append(signers, signer)
+	}
+	return signers, closeSigners, nil
+}
+
+// accounts sets up the accounts trading col, one per wallet it is restricted
+// to or per configured wallet.
+func (b *Bot) accounts(ctx context.Context, col *CollectionConfig, backend Backend, signers []Signer, store *OrderStore,
+	txManagers map[string]*TxManager) (*AccountManager, error) {
+	accounts := make([]*Account, 0, len(signers))
+	for _, signer := range signers {
+		opts := []Option{WithContract(col.Contract), WithChain(col.Chain), WithBackend(backend),
+			WithTxConfig(b.config.Transactions.txConfig())}
+		if signer != nil {
+			opts = append(opts, WithSigner(signer))
+		}
+		account, err := NewAccount(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if !col.tradedBy(account.WalletAddress()) {
+			continue
+		}
+		if col.PaymentToken != "" {
+			account.SetPaymentToken(col.PaymentToken)
+		}
+		account.SetApprovalConfirm(b.config.approvalConfirm())
+		key := col.Chain + "/" + account.WalletAddress().Hex()
+		if txs, ok := txManagers[key]; ok {
+			account.SetTxManager(txs)
+		} else {
+			txs, err := account.TxManager()
+			if err != nil {
+				return nil, err
+			}
+			txManagers[key] = txs
+			if store != nil {
+				txs.SetJournal(store)
+				if !b.dryRun {
+					b.recoverTxs(ctx, txs)
+				}
+			}
+		}
+		if store != nil {
+			account.SetOrderStore(store)
+			if err := account.ReconcileOrders(ctx); err != nil {
+				log.Printf("reconcile orders of %s: %v", account.WalletAddress().Hex(), err)
+			}
+		}
+		accounts = append(accounts, account)
+	}
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("%s: none of the wallets %v is configured", col.Contract, col.Wallets)
+	}
+	return NewAccountManager(accounts...)
+}
+
+// recoverTxs rebroadcasts the transactions a previous run left pending and
+// follows them in the background.
+func (b *Bot) recoverTxs(ctx context.Context, txs *TxManager) {
+	pending, err := txs.Recover(ctx)
+	if err != nil {
+		log.Printf("recover transactions: %v", err)
+		return
+	}
+	for _, p := range pending {
+		log.Println("recovered", p.Label, "nonce", p.Nonce, "tx", p.latest().Hash().Hex())
+		go func(p *PendingTx) {
+			receipt, err := txs.Wait(ctx, p)
+			if err != nil {
+				log.Printf("%s nonce %d: %v", p.Label, p.Nonce, err)
+				return
+			}
+			log.Println(p.Label, "mined in block", receipt.BlockNumber, "status", receipt.Status)
+		}(p)
+	}
+}
+
+func (b *Bot) sell(ctx context.Context, account *Account, col *CollectionConfig) error {
+	nfts, err := account.GetNFTs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, bundle := range col.Sell.Bundles {
+		b.sellBundle(ctx, account, col, bundle, nfts)
+	}
+
+	for i := range nfts.Nfts {
+		nft := &nfts.Nfts[i]
+		if col.Sell.bundled(col.Contract, nft.Identifier) {
+			continue
+		}
+		if col.Sell.AcceptOffer != "" && b.acceptOffer(ctx, account, col, nft) {
+			continue
+		}
+		if col.Sell.repriced(nft.Identifier) {
+			b.reprice(ctx, account, col, nft)
+			continue
+		}
+
+		if taker, ok := col.Sell.Private[nft.Identifier]; ok {
+			b.sellPrivately(ctx, account, col, nft, taker)
+			continue
+		}
+
+		quantity := col.Sell.QuantityFor(nft)
+		if quantity <= 0 {
+			continue
+		}
+		if col.Sell.auctioned(nft.Identifier) {
+			b.auction(ctx, account, col, nft, quantity)
+			continue
+		}
+
+		price, ok := col.Sell.PriceFor(nft.Identifier)
+		if !ok {
+			continue
+		}
+		log.Printf("list %d x %s #%s at %s for %d minutes", quantity, nft.Contract, nft.Identifier, price, col.Expire)
+		if b.dryRun {
+			continue
+		}
+		if _, err := account.CreateQuantityListing(ctx, nft, quantity, price, col.Expire); err != nil {
+			log.Printf("list %s #%s failed: %v", nft.Contract, nft.Identifier, err)
+			if errors.Is(err, ErrNotApproved) {
+				// the other listings of the collection need the same approval
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Bot) sellBundle(ctx context.Context, account *Account, col *CollectionConfig, bundle *BundleConfig, held *AccountNFTsResp) {
+	// validated when the configuration was loaded
+	items, _ := bundle.Items(col.Contract)
+	for i, item := range items {
+		if !strings.EqualFold(item.Contract, col.Contract) {
+			continue
+		}
+		nft := held.Get(item.Identifier)
+		if nft == nil {
+			log.Printf("bundle %v skipped: %s #%s is not held by %s", bundle.NFTs, item.Contract, item.Identifier, account.WalletAddress().Hex())
+			return
+		}
+		items[i] = nft
+	}
+
+	log.Printf("list bundle %v at %s for %d minutes", bundle.NFTs, bundle.Price, col.Expire)
+	if b.dryRun {
+		return
+	}
+	if _, err := account.CreateBundleListing(ctx, items, bundle.Price, col.Expire); err != nil {
+		log.Printf("bundle %v failed: %v", bundle.NFTs, err)
+	}
+}
+
+func (b *Bot) sellPrivately(ctx context.Context, account *Account, col *CollectionConfig, nft *NFT, taker string) {
+	price, _ := col.Sell.PriceFor(nft.Identifier)
+	log.Printf("list %s #%s privately to %s at %s for %d minutes", nft.Contract, nft.Identifier, taker, price, col.Expire)
+	if b.dryRun {
+		return
+	}
+	if _, err := account.CreatePrivateListing(ctx, nft, taker, price, col.Expire); err != nil {
+		log.Printf("private listing %s #%s failed: %v", nft.Contract, nft.Identifier, err)
+	}
+}
+
+func (b *Bot) auction(ctx context.Context, account *Account, col *CollectionConfig, nft *NFT, quantity int64) {
+	dutch := col.Sell.Dutch
+	log.Printf("auction %d x %s #%s from %s down to %s over %d minutes", quantity, nft.Contract, nft.Identifier,
+		dutch.StartPrice, dutch.EndPrice, dutch.Duration)
+	if b.dryRun {
+		return
+	}
+	if _, err := account.CreateDutchListing(ctx, nft, quantity, dutch.StartPrice, dutch.EndPrice, dutch.Duration); err != nil {
+		log.Printf("auction %s #%s failed: %v", nft.Contract, nft.Identifier, err)
+	}
+}
+
+func (b *Bot) repriceAll(ctx context.Context, account *Account, col *CollectionConfig) error {
+	nfts, err := account.GetNFTs(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range nfts.Nfts {
+		if col.Sell.repriced(nfts.Nfts[i].Identifier) {
+			b.reprice(ctx, account, col, &nfts.Nfts[i])
+		}
+	}
+	return nil
+}
+
+func (b *Bot) reprice(ctx context.Context, account *Account, col *CollectionConfig, nft *NFT) {
+	if b.dryRun {
+		log.Printf("reprice %s #%s", nft.Contract, nft.Identifier)
+		return
+	}
+	// validated when the configuration was loaded
+	rule, _ := col.Sell.Reprice.rule(col.Sell.Price)
+	if err := account.Reprice(ctx, nft, rule, col.Expire); err != nil {
+		log.Printf("reprice %s #%s failed: %v", nft.Contract, nft.Identifier, err)
+	}
+}
+
+// acceptOffer sells nft into its best offer if it clears sell.accept_offer and
+// reports whether the NFT was sold.
+func (b *Bot) acceptOffer(ctx context.Context, account *Account, col *CollectionConfig, nft *NFT) bool {
+	if b.dryRun {
+		log.Printf("accept best offer on %s #%s at or above %s", nft.Contract, nft.Identifier, col.Sell.AcceptOffer)
+		return false
+	}
+	receipt, err := account.AcceptBestOffer(ctx, nft, col.Sell.AcceptOffer)
+	if err != nil {
+		log.Printf("accept offer on %s #%s: %v", nft.Contract, nft.Identifier, err)
+		return false
+	}
+	log.Printf("sold %s #%s into best offer in block %s", nft.Contract, nft.Identifier, receipt.BlockNumber)
+	return true
+}
+
+func (b *Bot) buy(ctx context.Context, wallets *AccountManager, col *CollectionConfig) error {
+	if col.Buy.Sweep {
+		return b.sweep(ctx, wallets.Buyer(), col)
+	}
+
+	listings, err := wallets.Accounts()[0].GetBestListing(ctx, col.Buy.Limit)
+	if err != nil {
+		return err
+	}
+
+	maxPrice, _ := decimal.NewFromString(col.Buy.MaxPrice)
+	budget, _ := decimal.NewFromString(col.MaxSpend)
+	candidates, total := selectListings(listings, maxPrice, budget)
+	log.Printf("%d listings selected, total %s", len(candidates), total)
+	for i := range candidates {
+		listing := &candidates[i]
+		account := wallets.Buyer()
+		log.Printf("buy %s with %s", listing.OrderHash, account.WalletAddress().Hex())
+		if b.dryRun {
+			continue
+		}
+		receipt, err := account.BuyListing(ctx, listing)
+		if orderUnavailable(err) {
+			log.Printf("listing %s is no longer available: %v", listing.OrderHash, err)
+			continue
+		}
+		if err != nil {
+			log.Printf("buy %s failed: %v", listing.OrderHash, err)
+			continue
+		}
+		log.Printf("bought %s in block %s", listing.OrderHash, receipt.BlockNumber)
+	}
+	return nil
+}
+
+func (b *Bot) sweep(ctx context.Context, account *Account, col *CollectionConfig) error {
+	if b.dryRun {
+		log.Printf("sweep up to %d listings at or below %s", col.Buy.Limit, col.Buy.MaxPrice)
+		return nil
+	}
+	result, err := account.Sweep(ctx, col.Buy.Limit, col.Buy.MaxPrice, col.MaxSpend)
+	if err != nil {
+		log.Printf("sweep failed: %v", err)
+		return nil
+	}
+	log.Printf("swept %d listings (%d unfilled) in block %s", len(result.Filled), len(result.Unfilled), result.Receipt.BlockNumber)
+	return nil
+}
+
+func (b *Bot) offer(ctx context.Context, wallets *AccountManager, col *CollectionConfig) {
+	for identifier, price := range col.Offer.NFTs {
+		account := wallets.Buyer()
+		nft := account.NFT(identifier)
+		log.Printf("offer %s on %s #%s for %d minutes", price, nft.Contract, nft.Identifier, col.Expire)
+		if b.dryRun {
+			continue
+		}
+		if _, err := account.CreateOffer(ctx, nft, price, col.Expire); err != nil {
+			log.Printf("offer on %s #%s failed: %v", nft.Contract, nft.Identifier, err)
+			if errors.Is(err, ErrNotApproved) {
+				return
+			}
+		}
+	}
+
+	if offer := col.Offer.Collection; offer != nil {
+		log.Printf("collection offer %s x%d on %s", offer.Price, offer.Quantity, col.Contract)
+		if !b.dryRun {
+			if _, err := wallets.Buyer().CreateCollectionOffer(ctx, offer.Price, offer.Quantity, col.Expire); err != nil {
+				log.Printf("collection offer on %s failed: %v", col.Contract, err)
+			}
+		}
+	}
+	for _, offer := range col.Offer.Traits {
+		log.Printf("trait offer %s x%d on %s %s=%s", offer.Price, offer.Quantity, col.Contract, offer.Type, offer.Value)
+		if b.dryRun {
+			continue
+		}
+		if _, err := wallets.Buyer().CreateTraitOffer(ctx, offer.Type, offer.Value, offer.Price, offer.Quantity, col.Expire); err != nil {
+			log.Printf("trait offer on %s %s=%s failed: %v", col.Contract, offer.Type, offer.Value, err)
+		}
+	}
+}
+
+// selectListings keeps the listings priced at or below maxPrice, in the order
+// returned by OpenSea, until budget would be exceeded. A zero budget means no
+// limit.
+func selectListings(listings []BestListingResp, maxPrice, budget decimal.Decimal) ([]BestListingResp, decimal.Decimal) {
+	selected := make([]BestListingResp, 0)
+	total := decimal.Zero
+	for _, listing := range listings {
+		price, err := listing.CurrentPrice()
+		if err != nil {
+			log.Printf("skip listing %s: %v", listing.OrderHash, err)
+			continue
+		}
+		if price.GreaterThan(maxPrice) {
+			continue
+		}
+		if !budget.IsZero() && total.Add(price).GreaterThan(budget) {
+			continue
+		}
+		total = total.Add(price)
+		selected = append(selected, listing)
+	}
+	return selected, total
+}
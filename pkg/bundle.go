@@ -0,0 +1,201 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ErrMixedBundle is returned when OpenSea refuses a bundle spanning several
+// collections.
+var ErrMixedBundle = errors.New("opensea rejected a bundle across collections")
+
+// mixedBundleRejectionReasons are the phrases of OpenSea's rejection of a
+// bundle because of the collections in it.
+var mixedBundleRejectionReasons = []string{"single collection"}
+
+// mixedBundleRejection reports whether a rejected post names the collection
+// restriction, other rejections, of bundles too, keep their own reason.
+func mixedBundleRejection(err error) bool {
+	reason := strings.ToLower(err.Error())
+	for _, phrase := range mixedBundleRejectionReasons {
+		if strings.Contains(reason, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateBundleListing lists nfts, possibly from different contracts, together
+// for price in a single order. Each collection's required fees are charged on
+// its share of the price, the price being split evenly across the items.
+func (a *Account) CreateBundleListing(ctx context.Context, nfts []*NFT, price string, expire int) (*CreateListingResp, error) {
+	startTime := time.Now().Local().Unix()
+	endTime := time.Now().Local().Add(time.Duration(expire) * time.Minute).Unix()
+
+	if len(nfts) < 2 {
+		return nil, errors.New("a bundle needs at least two nfts")
+	}
+
+	paymentToken, err := a.paymentToken(ctx, a.paymentTokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	listPrice, err := decimal.NewFromString(price)
+	if err != nil {
+		return nil, err
+	}
+	if !listPrice.IsPositive() {
+		return nil, errors.New("price must be positive")
+	}
+	listPrice = listPrice.Shift(int32(paymentToken.Decimals))
+
+	offer := make([]OfferItem, 0, len(nfts))
+	shares := make([]bundleShare, 0)
+	collections := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, nft := range nfts {
+		key := strings.ToLower(nft.Contract) + ":" + nft.Identifier
+		if seen[key] {
+			return nil, fmt.Errorf("%s #%s is in the bundle twice", nft.Contract, nft.Identifier)
+		}
+		seen[key] = true
+
+		info, err := a.contractInfo(ctx, nft.Contract)
+		if err != nil {
+			return nil, err
+		}
+		if nft.TokenStandard == "" {
+			nft.TokenStandard = info.ContractStandard
+		}
+		identifier, err := nft.identifier()
+		if err != nil {
+			return nil, err
+		}
+		offer = append(offer, OfferItem{
+			ItemType:             nft.nftType(),
+			Token:                common.HexToAddress(nft.Contract).Hex(),
+			IdentifierOrCriteria: identifier,
+			StartAmount:          big.NewInt(1),
+			EndAmount:            big.NewInt(1),
+		})
+
+		if i, ok := collections[info.Collection]; ok {
+			shares[i].items++
+			continue
+		}
+		collection, err := a.getCollection(ctx, info.Collection)
+		if err != nil {
+			return nil, err
+		}
+		collections[info.Collection] = len(shares)
+		shares = append(shares, bundleShare{collection: collection, items: 1})
+	}
+
+	counter, err := a.seaportInstance.GetCounter(nil, a.WalletAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	considerations := bundleConsiderations(listPrice, shares, paymentToken, a.WalletAddress().Hex())
+	param := OrderParameters{
+		Offerer:                         a.WalletAddress().Hex(),
+		Zone:                            zeroAddress().Hex(),
+		ZoneHash:                        zero32BytesHexString(),
+		StartTime:                       startTime,
+		EndTime:                         endTime,
+		OrderType:                       0, // FULL_OPEN
+		Salt:                            fixedSalt(),
+		ConduitKey:                      a.chain.ConduitKey,
+		Offer:                           offer,
+		Consideration:                   considerations,
+		TotalOriginalConsiderationItems: len(considerations),
+		Counter:                         counter,
+	}
+
+	if err := a.ensureApprovals(ctx, param.Offer); err != nil {
+		return nil, err
+	}
+	data, err := param.signTypedData(a)
+	if err != nil {
+		return nil, err
+	}
+	orderHash, err := a.trackOrder(SideBundle, data, price)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := a.postOrder(ctx, "listings", orderHash, data)
+	if errors.Is(err, ErrOrderRejected) && len(shares) > 1 && mixedBundleRejection(err) {
+		return nil, fmt.Errorf("%w: %w", ErrMixedBundle, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.markOrder(orderHash, OrderActive)
+
+	log.Println(output)
+	return output, nil
+}
+
+type bundleShare struct {
+	collection *CollectionResp
+	items      int
+}
+
+// bundleConsiderations returns the seller proceeds followed by the fees of each
+// collection on its share of amount. Fees owed to the same recipient by several
+// collections are paid in a single item.
+func bundleConsiderations(amount decimal.Decimal, shares []bundleShare, paymentToken *paymentTokenResp, recipient string) []ConsiderationItem {
+	itemType := paymentToken.itemType()
+	total := 0
+	for _, share := range shares {
+		total += share.items
+	}
+
+	fees := make([]ConsiderationItem, 0)
+	byRecipient := make(map[string]int)
+	totalFee := decimal.Zero
+	for _, share := range shares {
+		part := amount.Mul(decimal.NewFromInt(int64(share.items))).Div(decimal.NewFromInt(int64(total)))
+		items, fee := share.collection.feeConsiderations(part, itemType, paymentToken.Address)
+		totalFee = totalFee.Add(fee)
+		for _, item := range items {
+			key := strings.ToLower(item.Recipient)
+			if i, ok := byRecipient[key]; ok {
+				fees[i].StartAmount = new(big.Int).Add(fees[i].StartAmount, item.StartAmount)
+				fees[i].EndAmount = new(big.Int).Add(fees[i].EndAmount, item.EndAmount)
+				continue
+			}
+			byRecipient[key] = len(fees)
+			fees = append(fees, item)
+		}
+	}
+
+	proceeds := amount.Sub(totalFee).BigInt()
+	return append([]ConsiderationItem{
+		{
+			ItemType:             itemType,
+			Token:                paymentToken.Address,
+			IdentifierOrCriteria: new(big.Int),
+			StartAmount:          proceeds,
+			EndAmount:            new(big.Int).Set(proceeds),
+			Recipient:            recipient,
+		},
+	}, fees...)
+}
+
+// contractInfo describes contract, from the account's own contract when it is
+// the same and from OpenSea otherwise.
+func (a *Account) contractInfo(ctx context.Context, contract string) (*contractInfo, error) {
+	if strings.EqualFold(contract, a.contract.Address) {
+		return a.contract, nil
+	}
+	return a.fetchContractInfo(ctx, a.contract.Chain, contract)
+}
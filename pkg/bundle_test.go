@@ -0,0 +1,51 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBundleConsiderations(t *testing.T) {
+	var first, second CollectionResp
+	require.Nil(t, json.Unmarshal([]byte(`{"collection":"first","fees":[{"fee":2.5,"recipient":"0x0000a26b00c1F0DF003000390027140000fAa719","required":true},{"fee":5,"recipient":"0x1111111111111111111111111111111111111111","required":true}]}`), &first))
+	require.Nil(t, json.Unmarshal([]byte(`{"collection":"second","fees":[{"fee":2.5,"recipient":"0x0000a26b00c1f0df003000390027140000faa719","required":true},{"fee":10,"recipient":"0x2222222222222222222222222222222222222222","required":false}]}`), &second))
+
+	amount := decimal.RequireFromString("3").Shift(18)
+	token := &paymentTokenResp{Address: zeroAddress().Hex(), Decimals: 18}
+	considerations := bundleConsiderations(amount, []bundleShare{{&first, 2}, {&second, 1}}, token, "0x9d1E5C9bA1c7B8fB8a4fA3e6d1DA6e8a25E4b4A2")
+
+	require.Len(t, considerations, 3)
+	// opensea fee on the whole price, merged across both collections
+	require.Equal(t, "75000000000000000", considerations[1].StartAmount.String())
+	// creator fee of the first collection on its 2 ETH share only
+	require.Equal(t, "100000000000000000", considerations[2].StartAmount.String())
+	require.Equal(t, "2825000000000000000", considerations[0].StartAmount.String())
+}
+
+func TestBundleConfig_Items(t *testing.T) {
+	bundle := &BundleConfig{Price: "1", NFTs: []string{"1", "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d:12"}}
+	nfts, err := bundle.Items("0x300b105942d6d181cdfe8199fd48eb09d26efd24")
+	require.Nil(t, err)
+	require.Len(t, nfts, 2)
+	require.Equal(t, "0x300b105942d6d181cdfe8199fd48eb09d26efd24", nfts[0].Contract)
+	require.Equal(t, "0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d", nfts[1].Contract)
+	require.Equal(t, "12", nfts[1].Identifier)
+
+	for _, items := range [][]string{{"1"}, {"1", "0x12:3"}, {"1", "x"}} {
+		_, err := (&BundleConfig{Price: "1", NFTs: items}).Items("0x300b105942d6d181cdfe8199fd48eb09d26efd24")
+		require.NotNil(t, err, items)
+	}
+}
+
+func TestMixedBundleRejection(t *testing.T) {
+	rejected := func(body string) error {
+		return fmt.Errorf("%w: 400 Bad Request: %s", ErrOrderRejected, body)
+	}
+	require.True(t, mixedBundleRejection(rejected(`{"errors": ["Bundles must contain items from a single collection"]}`)))
+	require.False(t, mixedBundleRejection(rejected(`{"errors": ["Invalid signature"]}`)))
+	require.False(t, mixedBundleRejection(rejected(`{"errors": ["Order has expired"]}`)))
+	require.False(t, mixedBundleRejection(rejected(`{"errors": ["Bundle price below minimum"]}`)))
+}
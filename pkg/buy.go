@@ -0,0 +1,322 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"log"
+	"math/big"
+	"opensea-bot/pkg/seaport"
+)
+
+// BuyListing fulfills listing on-chain and waits for the transaction to be mined.
+func (a *Account) BuyListing(ctx context.Context, listing *BestListingResp) (*ethtypes.Receipt, error) {
+	fulfillment, err := a.listingFulfillmentData(ctx, listing)
+	if err != nil {
+		return nil, err
+	}
+	if len(fulfillment.FulfillmentData.Orders) == 0 {
+		return nil, fmt.Errorf("no fulfillment order returned for %s", listing.OrderHash)
+	}
+
+	order, err := fulfillment.advancedOrder(0)
+	if err != nil {
+		return nil, err
+	}
+	address, err := fulfillment.seaportAddress(listing.ProtocolAddress)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := seaport.NewSeaport(address, a.client)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := a.transactOpts(ctx, nativeAmount(order.Parameters.Consideration))
+	tx, err := instance.FulfillAdvancedOrder(opts, *order, []seaport.CriteriaResolver{},
+		hexStringToByte32(a.chain.ConduitKey), a.WalletAddress())
+	if err != nil {
+		return nil, err
+	}
+	return a.sendTx(ctx, "fulfill listing "+listing.OrderHash, tx)
+}
+
+// ErrUnknownSeaport is returned when OpenSea points a fulfillment at a contract
+// that is not a known Seaport deployment.
+var ErrUnknownSeaport = errors.New("not a known seaport deployment")
+
+// seaportAddress is the Seaport contract the fulfillment transaction targets,
+// the order's protocol address when OpenSea leaves it out. Orders must be
+// fulfilled on the Seaport version they were signed for.
+func (f *FulfillmentDataResp) seaportAddress(protocolAddress string) (common.Address, error) {
+	to := f.FulfillmentData.Transaction.To
+	if to == "" {
+		to = protocolAddress
+	}
+	if !common.IsHexAddress(to) {
+		return common.Address{}, fmt.Errorf("%w: %q", ErrUnknownSeaport, to)
+	}
+	address := common.HexToAddress(to)
+	if _, ok := seaportVersions[address]; !ok {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrUnknownSeaport, address.Hex())
+	}
+	return address, nil
+}
+
+func (a *Account) listingFulfillmentData(ctx context.Context, listing *BestListingResp) (*FulfillmentDataResp, error) {
+	protocolAddress := listing.ProtocolAddress
+	if protocolAddress == "" {
+		protocolAddress = a.chain.SeaportAddress
+	}
+	body := map[string]interface{}{
+		"listing": map[string]string{
+			"hash":             listing.OrderHash,
+			"chain":            a.contract.Chain,
+			"protocol_address": protocolAddress,
+		},
+		"fulfiller": map[string]string{
+			"address": a.WalletAddress().Hex(),
+		},
+	}
+
+	var data *FulfillmentDataResp
+	req := a.newRequest().
+		Post(fmt.Sprintf("%s/api/v2/listings/fulfillment_data", getOpenSeaAPI(a.contract.Chain))).Send(body)
+	log.Println(req.AsCurlCommand())
+	resp, _, errs := req.EndStruct(&data)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	log.Println(resp)
+	return data, nil
+}
+
+// transactOpts makes the contract bindings only pack a transaction, sendTx
+// then simulates it and has the account's TxManager price, sign and send it.
+func (a *Account) transactOpts(ctx context.Context, value *big.Int) *bind.TransactOpts {
+	from := a.WalletAddress()
+	return &bind.TransactOpts{
+		From: from,
+		// the TxManager assigns the nonce and simulate estimates the gas
+		Nonce:    new(big.Int),
+		GasLimit: 1,
+		Signer: func(address common.Address, tx *ethtypes.Transaction) (*ethtypes.Transaction, error) {
+			if address != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return tx, nil
+		},
+		Value:   value,
+		Context: ctx,
+		NoSend:  true,
+	}
+}
+
+// sendTx simulates tx, then sends it through the account's TxManager and waits
+// for it to be confirmed. Nothing is broadcast when the simulation reverts.
+func (a *Account) sendTx(ctx context.Context, label string, tx *ethtypes.Transaction) (*ethtypes.Receipt, error) {
+	tx, err := a.simulate(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", label, err)
+	}
+	txs, err := a.TxManager()
+	if err != nil {
+		return nil, err
+	}
+	pending, err := txs.Send(ctx, label, tx)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := txs.Wait(ctx, pending)
+	if err != nil {
+		return receipt, err
+	}
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		return receipt, a.minedRevert(ctx, tx, receipt)
+	}
+	return receipt, nil
+}
+
+// advancedOrder builds the seaport.AdvancedOrder for the i-th order of the
+// fulfillment data. The fill fraction and zone extra data OpenSea supplied are
+// carried over, defaulting to a full fill.
+func (f *FulfillmentDataResp) advancedOrder(i int) (*seaport.AdvancedOrder, error) {
+	order := f.FulfillmentData.Orders[i]
+	parameters, err := order.Parameters.toSeaport()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := hexutil.Decode(order.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	}
+
+	input, err := f.inputData()
+	if err != nil {
+		return nil, err
+	}
+
+	advancedOrder := &seaport.AdvancedOrder{
+		Parameters:  *parameters,
+		Numerator:   big.NewInt(1),
+		Denominator: big.NewInt(1),
+		Signature:   signature,
+		ExtraData:   []byte{},
+	}
+	if input.AdvancedOrder != nil {
+		if input.AdvancedOrder.Numerator != nil && input.AdvancedOrder.Denominator != nil {
+			advancedOrder.Numerator = input.AdvancedOrder.Numerator.Int
+			advancedOrder.Denominator = input.AdvancedOrder.Denominator.Int
+		}
+		if input.AdvancedOrder.ExtraData != "" {
+			advancedOrder.ExtraData, err = hexutil.Decode(input.AdvancedOrder.ExtraData)
+			if err != nil {
+				return nil, fmt.Errorf("decode extra data: %w", err)
+			}
+		}
+	}
+	return advancedOrder, nil
+}
+
+func (f *FulfillmentDataResp) criteriaResolvers() ([]seaport.CriteriaResolver, error) {
+	input, err := f.inputData()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]seaport.CriteriaResolver, 0, len(input.CriteriaResolvers))
+	for _, r := range input.CriteriaResolvers {
+		proof := make([][32]byte, 0, len(r.CriteriaProof))
+		for _, node := range r.CriteriaProof {
+			proof = append(proof, hexStringToByte32(node))
+		}
+		resolvers = append(resolvers, seaport.CriteriaResolver{
+			OrderIndex:    r.OrderIndex.Int,
+			Side:          uint8(r.Side.Uint64()),
+			Index:         r.Index.Int,
+			Identifier:    r.Identifier.Int,
+			CriteriaProof: proof,
+		})
+	}
+	return resolvers, nil
+}
+
+func (f *FulfillmentDataResp) inputData() (*fulfillmentInputData, error) {
+	var input fulfillmentInputData
+	if len(f.FulfillmentData.Transaction.InputData) > 0 {
+		if err := json.Unmarshal(f.FulfillmentData.Transaction.InputData, &input); err != nil {
+			return nil, fmt.Errorf("decode input data: %w", err)
+		}
+	}
+	return &input, nil
+}
+
+func (p *Parameters) toSeaport() (*seaport.OrderParameters, error) {
+	offer := make([]seaport.OfferItem, 0, len(p.Offer))
+	for _, item := range p.Offer {
+		identifier, start, end, err := parseItemAmounts(item.IdentifierOrCriteria, item.StartAmount, item.EndAmount)
+		if err != nil {
+			return nil, fmt.Errorf("offer: %w", err)
+		}
+		offer = append(offer, seaport.OfferItem{
+			ItemType:             uint8(item.ItemType),
+			Token:                common.HexToAddress(item.Token),
+			IdentifierOrCriteria: identifier,
+			StartAmount:          start,
+			EndAmount:            end,
+		})
+	}
+
+	consideration := make([]seaport.ConsiderationItem, 0, len(p.Consideration))
+	for _, item := range p.Consideration {
+		identifier, start, end, err := parseItemAmounts(item.IdentifierOrCriteria, item.StartAmount, item.EndAmount)
+		if err != nil {
+			return nil, fmt.Errorf("consideration: %w", err)
+		}
+		consideration = append(consideration, seaport.ConsiderationItem{
+			ItemType:             uint8(item.ItemType),
+			Token:                common.HexToAddress(item.Token),
+			IdentifierOrCriteria: identifier,
+			StartAmount:          start,
+			EndAmount:            end,
+			Recipient:            common.HexToAddress(item.Recipient),
+		})
+	}
+
+	startTime, err := parseBigInt(p.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("startTime: %w", err)
+	}
+	endTime, err := parseBigInt(p.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("endTime: %w", err)
+	}
+	salt, err := parseBigInt(p.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("salt: %w", err)
+	}
+
+	return &seaport.OrderParameters{
+		Offerer:                         common.HexToAddress(p.Offerer),
+		Zone:                            common.HexToAddress(p.Zone),
+		Offer:                           offer,
+		Consideration:                   consideration,
+		OrderType:                       uint8(p.OrderType),
+		StartTime:                       startTime,
+		EndTime:                         endTime,
+		ZoneHash:                        hexStringToByte32(p.ZoneHash),
+		Salt:                            salt,
+		ConduitKey:                      hexStringToByte32(p.ConduitKey),
+		TotalOriginalConsiderationItems: big.NewInt(int64(p.TotalOriginalConsiderationItems)),
+	}, nil
+}
+
+func parseItemAmounts(identifier, start, end string) (*big.Int, *big.Int, *big.Int, error) {
+	id, err := parseBigInt(identifier)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("identifierOrCriteria: %w", err)
+	}
+	startAmount, err := parseBigInt(start)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("startAmount: %w", err)
+	}
+	endAmount, err := parseBigInt(end)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("endAmount: %w", err)
+	}
+	return id, startAmount, endAmount, nil
+}
+
+func parseBigInt(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, errors.New("empty value")
+	}
+	n, ok := new(big.Int).SetString(value, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", value)
+	}
+	return n, nil
+}
+
+// nativeAmount sums the native token consideration the fulfiller has to send.
+// The larger of start and end amount is used so that ascending or descending
+// auctions are always covered; Seaport refunds any surplus.
+func nativeAmount(items []seaport.ConsiderationItem) *big.Int {
+	total := big.NewInt(0)
+	for _, item := range items {
+		if item.ItemType != 0 {
+			continue
+		}
+		if item.StartAmount.Cmp(item.EndAmount) > 0 {
+			total.Add(total, item.StartAmount)
+		} else {
+			total.Add(total, item.EndAmount)
+		}
+	}
+	return total
+}
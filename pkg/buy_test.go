@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const fulfillmentFixture = `{
+	"protocol": "seaport1.6",
+	"fulfillment_data": {
+		"transaction": {
+			"function": "fulfillAdvancedOrder(((address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256),uint120,uint120,bytes,bytes),(uint256,uint8,uint256,uint256,bytes32[])[],bytes32,address)",
+			"chain": 11155111,
+			"to": "0x0000000000000068f116a894984e2db1123eb395",
+			"input_data": {"advancedOrder": {"extraData": "0x0102"}}
+		},
+		"orders": [{
+			"parameters": {
+				"offerer": "0x9d1e5c9ba1c7b8fb8a4fa3e6d1da6e8a25e4b4a2",
+				"offer": [{"itemType": 2, "token": "0x300b105942d6d181cdfe8199fd48eb09d26efd24", "identifierOrCriteria": "115792089237316195423570985008687907853269984665640564039457584007913129639935", "startAmount": "1", "endAmount": "1"}],
+				"consideration": [
+					{"itemType": 0, "token": "0x0000000000000000000000000000000000000000", "identifierOrCriteria": "0", "startAmount": "9750000000000000000", "endAmount": "9750000000000000000", "recipient": "0x9d1e5c9ba1c7b8fb8a4fa3e6d1da6e8a25e4b4a2"},
+					{"itemType": 0, "token": "0x0000000000000000000000000000000000000000", "identifierOrCriteria": "0", "startAmount": "250000000000000000", "endAmount": "200000000000000000", "recipient": "0x0000a26b00c1f0df003000390027140000faa719"}
+				],
+				"startTime": "1700000000",
+				"endTime": "1700003600",
+				"orderType": 0,
+				"zone": "0x0000000000000000000000000000000000000000",
+				"zoneHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+				"salt": "0x360c6ebe",
+				"conduitKey": "0x0000007b02230091a7ed01230072f7006a004d60a8d4e71d599b8104250f0000",
+				"totalOriginalConsiderationItems": 2,
+				"counter": 0
+			},
+			"signature": "0xabcd"
+		}]
+	}
+}`
+
+func TestFulfillmentDataResp_AdvancedOrder(t *testing.T) {
+	var resp FulfillmentDataResp
+	require.Nil(t, json.Unmarshal([]byte(fulfillmentFixture), &resp))
+
+	order, err := resp.advancedOrder(0)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0xab, 0xcd}, order.Signature)
+	require.Equal(t, []byte{0x01, 0x02}, order.ExtraData)
+	require.Equal(t, int64(1), order.Numerator.Int64())
+
+	params := order.Parameters
+	require.Equal(t, common.HexToAddress("0x9d1e5c9ba1c7b8fb8a4fa3e6d1da6e8a25e4b4a2"), params.Offerer)
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	require.Equal(t, 0, params.Offer[0].IdentifierOrCriteria.Cmp(maxUint256))
+	require.Equal(t, "9750000000000000000", params.Consideration[0].StartAmount.String())
+	require.Equal(t, int64(0x360c6ebe), params.Salt.Int64())
+	require.Equal(t, int64(2), params.TotalOriginalConsiderationItems.Int64())
+	require.Equal(t, hexStringToByte32(SeaportConduitKey), params.ConduitKey)
+
+	require.Equal(t, "10000000000000000000", nativeAmount(params.Consideration).String())
+}
+
+func TestParameters_ToSeaportInvalid(t *testing.T) {
+	p := Parameters{StartTime: "1", EndTime: "2", Salt: "abc"}
+	_, err := p.toSeaport()
+	require.NotNil(t, err)
+}
+
+func TestBuyListing_FulfillsOnOrderSeaport(t *testing.T) {
+	fixture := fulfillmentFixture
+	client := apiClient(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fixture))
+	})
+	backend := &sendingBackend{}
+	account := testAccount(t, backend, client)
+	listing := &BestListingResp{OrderHash: "0x01", ProtocolAddress: SeaportV16Address}
+
+	_, err := account.BuyListing(context.Background(), listing)
+	require.Nil(t, err)
+	require.Len(t, backend.sent, 1)
+	require.Equal(t, common.HexToAddress("0x0000000000000068f116a894984e2db1123eb395"), *backend.sent[0].To())
+
+	fixture = strings.Replace(fulfillmentFixture, "0x0000000000000068f116a894984e2db1123eb395", testContract, 1)
+	_, err = account.BuyListing(context.Background(), listing)
+	require.True(t, errors.Is(err, ErrUnknownSeaport))
+	require.Len(t, backend.sent, 1)
+}
@@ -0,0 +1,186 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"log"
+	"net/http"
+	"opensea-bot/pkg/seaport"
+)
+
+// CancelOrders cancels orders on-chain in a single Seaport.Cancel transaction.
+// Only orders offered by the account can be cancelled.
+func (a *Account) CancelOrders(ctx context.Context, orders []Parameters) (*ethtypes.Receipt, error) {
+	if len(orders) == 0 {
+		return nil, errors.New("no orders to cancel")
+	}
+
+	components := make([]seaport.OrderComponents, 0, len(orders))
+	for i := range orders {
+		order, err := orders[i].toOrderComponents()
+		if err != nil {
+			return nil, fmt.Errorf("order %d: %w", i, err)
+		}
+		if order.Offerer != a.WalletAddress() {
+			return nil, fmt.Errorf("order %d is offered by %s, not %s", i, order.Offerer.Hex(), a.WalletAddress().Hex())
+		}
+		components = append(components, *order)
+	}
+	return a.cancelComponents(ctx, components)
+}
+
+// cancelStored cancels orders of the order store in a single Seaport.Cancel
+// transaction.
+func (a *Account) cancelStored(ctx context.Context, orders []*StoredOrder) (*ethtypes.Receipt, error) {
+	components := make([]seaport.OrderComponents, 0, len(orders))
+	for _, order := range orders {
+		component, err := order.Parameters.toOrderComponents()
+		if err != nil {
+			return nil, fmt.Errorf("order %s: %w", order.OrderHash, err)
+		}
+		components = append(components, *component)
+	}
+	return a.cancelComponents(ctx, components)
+}
+
+func (a *Account) cancelComponents(ctx context.Context, components []seaport.OrderComponents) (*ethtypes.Receipt, error) {
+	tx, err := a.seaportInstance.Cancel(a.transactOpts(ctx, nil), components)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := a.sendTx(ctx, fmt.Sprintf("cancel %d orders", len(components)), tx)
+	if err != nil {
+		return receipt, err
+	}
+	a.markCancelled(receipt)
+	return receipt, nil
+}
+
+// CancelAll increments the account's Seaport counter, which invalidates every
+// order it has signed so far.
+func (a *Account) CancelAll(ctx context.Context) (*ethtypes.Receipt, error) {
+	tx, err := a.seaportInstance.IncrementCounter(a.transactOpts(ctx, nil))
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := a.sendTx(ctx, "increment counter", tx)
+	if err != nil {
+		return receipt, err
+	}
+	if a.store != nil {
+		if err := a.ReconcileOrders(ctx); err != nil {
+			log.Printf("reconcile orders: %v", err)
+		}
+	}
+	return receipt, nil
+}
+
+var (
+	// ErrNoSignedZone is returned when an order OpenSea cannot cancel off-chain
+	// is passed to CancelOrderOffChain.
+	ErrNoSignedZone = errors.New("order does not use the opensea signed zone")
+	// ErrCancelRejected is returned when OpenSea refuses an off-chain cancel.
+	ErrCancelRejected = errors.New("cancel rejected by opensea")
+)
+
+// CancelOrderOffChain asks OpenSea to cancel an order without a transaction.
+// OpenSea only supports this for orders using its signed zone, other orders
+// fail with ErrNoSignedZone; the order then stays fillable until the last zone
+// signature OpenSea issued expires.
+func (a *Account) CancelOrderOffChain(ctx context.Context, orderHash string) (*CancelOrderResp, error) {
+	order, err := a.GetOrder(ctx, orderHash)
+	if err != nil {
+		return nil, err
+	}
+	if !hasZone(&order.ProtocolData.Parameters) {
+		return nil, fmt.Errorf("%w: %s", ErrNoSignedZone, orderHash)
+	}
+	return a.cancelOffChain(ctx, orderHash)
+}
+
+// CancelByHash cancels orders through OpenSea when they use its signed zone
+// and the others with a single Seaport transaction. The receipt is nil when no
+// order needed one.
+func (a *Account) CancelByHash(ctx context.Context, orderHashes []string) (*ethtypes.Receipt, error) {
+	var onChain []Parameters
+	for _, hash := range orderHashes {
+		order, err := a.GetOrder(ctx, hash)
+		if err != nil {
+			return nil, fmt.Errorf("order %s: %w", hash, err)
+		}
+		if !hasZone(&order.ProtocolData.Parameters) {
+			onChain = append(onChain, order.ProtocolData.Parameters)
+			continue
+		}
+		if _, err := a.cancelOffChain(ctx, hash); err != nil {
+			return nil, fmt.Errorf("order %s: %w", hash, err)
+		}
+	}
+	if len(onChain) == 0 {
+		return nil, nil
+	}
+	return a.CancelOrders(ctx, onChain)
+}
+
+func (a *Account) cancelOffChain(ctx context.Context, orderHash string) (*CancelOrderResp, error) {
+	var data *CancelOrderResp
+	req := a.newRequest().
+		Post(fmt.Sprintf("%s/api/v2/orders/chain/%s/protocol/%s/%s/cancel",
+			getOpenSeaAPI(a.contract.Chain), a.contract.Chain, a.chain.SeaportAddress, orderHash))
+	log.Println(req.AsCurlCommand())
+	resp, body, errs := req.EndStruct(&data)
+	if resp != nil && resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%w: %s: %s", ErrCancelRejected, resp.Status, body)
+	}
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	log.Println(resp)
+	a.markOrder(orderHash, OrderCancelled)
+	return data, nil
+}
+
+// ErrUnknownOrder is returned by GetOrder when OpenSea has no such order.
+var ErrUnknownOrder = errors.New("order unknown to opensea")
+
+func (a *Account) GetOrder(ctx context.Context, orderHash string) (*OrderResp, error) {
+	var data *CreateListingResp
+	req := a.newRequest().
+		Get(fmt.Sprintf("%s/api/v2/orders/chain/%s/protocol/%s/%s",
+			getOpenSeaAPI(a.contract.Chain), a.contract.Chain, a.chain.SeaportAddress, orderHash))
+	log.Println(req.AsCurlCommand())
+	resp, body, errs := req.EndStruct(&data)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownOrder, orderHash)
+	}
+	if resp != nil && resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("get order %s: %s: %s", orderHash, resp.Status, body)
+	}
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	log.Println(resp)
+	return &data.Order, nil
+}
+
+func (p *Parameters) toOrderComponents() (*seaport.OrderComponents, error) {
+	params, err := p.toSeaport()
+	if err != nil {
+		return nil, err
+	}
+	return &seaport.OrderComponents{
+		Offerer:       params.Offerer,
+		Zone:          params.Zone,
+		Offer:         params.Offer,
+		Consideration: params.Consideration,
+		OrderType:     params.OrderType,
+		StartTime:     params.StartTime,
+		EndTime:       params.EndTime,
+		ZoneHash:      params.ZoneHash,
+		Salt:          params.Salt,
+		ConduitKey:    params.ConduitKey,
+		Counter:       p.Counter.orZero(),
+	}, nil
+}
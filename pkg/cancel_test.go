@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestParameters_ToOrderComponents(t *testing.T) {
+	var resp FulfillmentDataResp
+	require.Nil(t, json.Unmarshal([]byte(fulfillmentFixture), &resp))
+
+	params := resp.FulfillmentData.Orders[0].Parameters
+	params.Counter = apiBigInt{big.NewInt(3)}
+	components, err := params.toOrderComponents()
+	require.Nil(t, err)
+	require.Equal(t, int64(3), components.Counter.Int64())
+	require.Len(t, components.Consideration, 2)
+	require.Equal(t, int64(1700003600), components.EndTime.Int64())
+}
+
+func TestCancelOrderOffChain(t *testing.T) {
+	store, err := OpenOrderStore(filepath.Join(t.TempDir(), "store"))
+	require.Nil(t, err)
+	defer store.Close()
+
+	zone := zeroAddress().Hex()
+	var cancels int
+	client := apiClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			cancels++
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": ["order not found"]}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"order": {"order_hash": "0x01", "protocol_data": {"parameters": {"zone": %q}}}}`, zone)
+	})
+	account := testAccount(t, nil, client)
+	account.SetOrderStore(store)
+	require.Nil(t, store.Put(&StoredOrder{OrderHash: "0x01", Chain: "sepolia", Side: SideListing, Status: OrderActive}))
+	ctx := context.Background()
+
+	_, err = account.CancelOrderOffChain(ctx, "0x01")
+	require.True(t, errors.Is(err, ErrNoSignedZone))
+	require.Zero(t, cancels)
+
+	zone = "0x000056F7000000EcE9003ca63978907a00FFD100"
+	_, err = account.CancelOrderOffChain(ctx, "0x01")
+	require.True(t, errors.Is(err, ErrCancelRejected))
+	require.Equal(t, 1, cancels)
+	order, err := store.Get("0x01")
+	require.Nil(t, err)
+	require.Equal(t, OrderActive, order.Status)
+}
+
+func TestGetOrder_Unknown(t *testing.T) {
+	status := http.StatusNotFound
+	client := apiClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"errors": ["not found"]}`))
+	})
+	backend := newApprovalBackend(t)
+	account := approvalAccount(t, backend)
+	var err error
+	account.http, err = httpAgent(client)
+	require.Nil(t, err)
+	ctx := context.Background()
+
+	_, err = account.GetOrder(ctx, "0x01")
+	require.True(t, errors.Is(err, ErrUnknownOrder))
+
+	// an order OpenSea never accepted does not become active
+	order := &StoredOrder{OrderHash: "0x01", Status: OrderCreated, Parameters: OrderParameters{Counter: new(big.Int)}}
+	got, err := account.orderStatus(ctx, order, new(big.Int))
+	require.Nil(t, err)
+	require.Equal(t, OrderCreated, got)
+
+	status = http.StatusInternalServerError
+	_, err = account.GetOrder(ctx, "0x01")
+	require.NotNil(t, err)
+	require.False(t, errors.Is(err, ErrUnknownOrder))
+	_, err = account.orderStatus(ctx, order, new(big.Int))
+	require.NotNil(t, err)
+}
@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// OpenSeaConduitAddress is the conduit behind SeaportConduitKey, deployed at
+// the same address on every chain.
+const OpenSeaConduitAddress = "0x1E0049783F008A0085193E00003D00cd54003c71"
+
+// Chain describes a network supported by OpenSea. Name is OpenSea's chain
+// identifier, used in API paths and in the configuration.
+type Chain struct {
+	Name    string
+	ChainID int64
+	Testnet bool
+	// OpenSeaAPI is the API base URL, mainnets and testnets use different ones.
+	OpenSeaAPI string
+	// RPCURL is expanded with os.ExpandEnv, ${INFURA_KEY} is the usual key.
+	RPCURL string
+	// NativeToken is the symbol of the gas token.
+	NativeToken string
+	// PaymentToken is the default listing currency, the zero address for the
+	// native token.
+	PaymentToken string
+	// WrappedToken is the ERC20 offers are paid in.
+	WrappedToken string
+	// SeaportAddress is the Seaport deployment orders are signed for and
+	// posted to, Seaport 1.6 unless a chain sets it, e.g. to ProtocolAddress
+	// for Seaport 1.5.
+	SeaportAddress string
+	ConduitKey     string
+	ConduitAddress string
+}
+
+var chains = map[string]*Chain{}
+
+// chainAliases maps common names to OpenSea's chain identifiers.
+var chainAliases = map[string]string{
+	"mainnet": "ethereum",
+	"polygon": "matic",
+}
+
+func init() {
+	for _, c := range []Chain{
+		{Name: "ethereum", ChainID: 1, RPCURL: "https://mainnet.infura.io/v3/${INFURA_KEY}", NativeToken: "ETH",
+			WrappedToken: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"},
+		{Name: "sepolia", ChainID: 11155111, Testnet: true, RPCURL: "https://sepolia.infura.io/v3/${INFURA_KEY}", NativeToken: "ETH",
+			WrappedToken: "0x7b79995e5f793A07Bc00c21412e50Ecae098E7f9"},
+		// OpenSea lists and bids in bridged WETH on Polygon
+		{Name: "matic", ChainID: 137, RPCURL: "https://polygon-mainnet.infura.io/v3/${INFURA_KEY}", NativeToken: "POL",
+			PaymentToken: "0x7ceB23fD6bC0adD59E62ac25578270cFf1b9f619", WrappedToken: "0x7ceB23fD6bC0adD59E62ac25578270cFf1b9f619"},
+		{Name: "amoy", ChainID: 80002, Testnet: true, RPCURL: "https://polygon-amoy.infura.io/v3/${INFURA_KEY}", NativeToken: "POL"},
+		{Name: "base", ChainID: 8453, RPCURL: "https://base-mainnet.infura.io/v3/${INFURA_KEY}", NativeToken: "ETH",
+			WrappedToken: "0x4200000000000000000000000000000000000006"},
+		{Name: "base_sepolia", ChainID: 84532, Testnet: true, RPCURL: "https://base-sepolia.infura.io/v3/${INFURA_KEY}", NativeToken: "ETH",
+			WrappedToken: "0x4200000000000000000000000000000000000006"},
+		{Name: "arbitrum", ChainID: 42161, RPCURL: "https://arbitrum-mainnet.infura.io/v3/${INFURA_KEY}", NativeToken: "ETH",
+			WrappedToken: "0x82aF49447D8a07e3bd95BD0d56f35241523fBab1"},
+		{Name: "arbitrum_sepolia", ChainID: 421614, Testnet: true, RPCURL: "https://arbitrum-sepolia.infura.io/v3/${INFURA_KEY}", NativeToken: "ETH",
+			WrappedToken: "0x980B62Da83eFf3D4576C647993b0c1D7faf17c73"},
+		{Name: "optimism", ChainID: 10, RPCURL: "https://optimism-mainnet.infura.io/v3/${INFURA_KEY}", NativeToken: "ETH",
+			WrappedToken: "0x4200000000000000000000000000000000000006"},
+		{Name: "optimism_sepolia", ChainID: 11155420, Testnet: true, RPCURL: "https://optimism-sepolia.infura.io/v3/${INFURA_KEY}", NativeToken: "ETH",
+			WrappedToken: "0x4200000000000000000000000000000000000006"},
+		{Name: "blast", ChainID: 81457, RPCURL: "https://blast-mainnet.infura.io/v3/${INFURA_KEY}", NativeToken: "ETH",
+			WrappedToken: "0x4300000000000000000000000000000000000004"},
+		{Name: "blast_sepolia", ChainID: 168587773, Testnet: true, RPCURL: "https://blast-sepolia.infura.io/v3/${INFURA_KEY}", NativeToken: "ETH",
+			WrappedToken: "0x4200000000000000000000000000000000000023"},
+		{Name: "zora", ChainID: 7777777, RPCURL: "https://rpc.zora.energy", NativeToken: "ETH",
+			WrappedToken: "0x4200000000000000000000000000000000000006"},
+		{Name: "zora_sepolia", ChainID: 999999999, Testnet: true, RPCURL: "https://sepolia.rpc.zora.energy", NativeToken: "ETH",
+			WrappedToken: "0x4200000000000000000000000000000000000006"},
+	} {
+		RegisterChain(c)
+	}
+}
+
+// RegisterChain adds c to the registry, or replaces the chain of the same name.
+// Unset OpenSea API, payment token and Seaport fields get the defaults shared
+// by every OpenSea chain.
+func RegisterChain(c Chain) {
+	if c.OpenSeaAPI == "" {
+		c.OpenSeaAPI = apiDomain
+		if c.Testnet {
+			c.OpenSeaAPI = testnetApiDomain
+		}
+	}
+	if c.PaymentToken == "" {
+		c.PaymentToken = zeroAddress().Hex()
+	}
+	if c.SeaportAddress == "" {
+		c.SeaportAddress = SeaportV16Address
+	}
+	if c.ConduitKey == "" {
+		c.ConduitKey = SeaportConduitKey
+	}
+	if c.ConduitAddress == "" {
+		c.ConduitAddress = OpenSeaConduitAddress
+	}
+	chains[c.Name] = &c
+}
+
+// LookupChain returns the registered chain called name, or one of its aliases.
+func LookupChain(name string) (*Chain, error) {
+	name = strings.ToLower(name)
+	if alias, ok := chainAliases[name]; ok {
+		name = alias
+	}
+	c, ok := chains[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported chain %q, known chains: %s", name, strings.Join(ChainNames(), ", "))
+	}
+	return c, nil
+}
+
+// ChainNames lists the registered chains in alphabetical order.
+func ChainNames() []string {
+	names := make([]string, 0, len(chains))
+	for name := range chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Chain) rpcURL() string {
+	return os.ExpandEnv(c.RPCURL)
+}
+
+func getOpenSeaAPI(chain string) string {
+	c, err := LookupChain(chain)
+	if err != nil {
+		return apiDomain
+	}
+	return c.OpenSeaAPI
+}
@@ -0,0 +1,58 @@
+package pkg
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLookupChain(t *testing.T) {
+	c, err := LookupChain("polygon")
+	require.Nil(t, err)
+	require.Equal(t, "matic", c.Name)
+	require.Equal(t, int64(137), c.ChainID)
+	require.Equal(t, apiDomain, c.OpenSeaAPI)
+	require.Equal(t, c.WrappedToken, c.PaymentToken)
+
+	c, err = LookupChain("base_sepolia")
+	require.Nil(t, err)
+	require.Equal(t, testnetApiDomain, c.OpenSeaAPI)
+	require.Equal(t, zeroAddress().Hex(), c.PaymentToken)
+
+	_, err = LookupChain("moon")
+	require.NotNil(t, err)
+}
+
+func TestChainRegistry(t *testing.T) {
+	t.Setenv("INFURA_KEY", "secret")
+	ids := make(map[int64]string)
+	for _, name := range ChainNames() {
+		c, err := LookupChain(name)
+		require.Nil(t, err)
+		require.NotContains(t, ids, c.ChainID, name)
+		ids[c.ChainID] = name
+
+		require.NotContains(t, c.rpcURL(), "${", name)
+		require.True(t, common.IsHexAddress(c.SeaportAddress), name)
+		require.True(t, common.IsHexAddress(c.ConduitAddress), name)
+		if c.WrappedToken != "" {
+			require.True(t, common.IsHexAddress(c.WrappedToken), name)
+		}
+	}
+	require.Len(t, ids, 14)
+
+	c, _ := LookupChain("ethereum")
+	require.Equal(t, "https://mainnet.infura.io/v3/secret", c.rpcURL())
+	for _, name := range []string{"ethereum", "base", "blast", "zora"} {
+		c, _ = LookupChain(name)
+		require.Equal(t, SeaportV16Address, c.SeaportAddress, name)
+	}
+}
+
+func TestRegisterChain_SeaportOverride(t *testing.T) {
+	defer delete(chains, "legacy")
+	RegisterChain(Chain{Name: "legacy", ChainID: 1337, SeaportAddress: ProtocolAddress})
+	c, err := LookupChain("legacy")
+	require.Nil(t, err)
+	require.Equal(t, ProtocolAddress, c.SeaportAddress)
+}
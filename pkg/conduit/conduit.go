@@ -0,0 +1,226 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package conduit
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// ConduitControllerMetaData contains all meta data concerning the ConduitController contract.
+var ConduitControllerMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"name\":\"conduitKey\",\"type\":\"bytes32\"}],\"name\":\"getConduit\",\"outputs\":[{\"name\":\"conduit\",\"type\":\"address\"},{\"name\":\"exists\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// ConduitControllerABI is the input ABI used to generate the binding from.
+// Deprecated: Use ConduitControllerMetaData.ABI instead.
+var ConduitControllerABI = ConduitControllerMetaData.ABI
+
+// ConduitController is an auto generated Go binding around an Ethereum contract.
+type ConduitController struct {
+	ConduitControllerCaller     // Read-only binding to the contract
+	ConduitControllerTransactor // Write-only binding to the contract
+	ConduitControllerFilterer   // Log filterer for contract events
+}
+
+// ConduitControllerCaller is an auto generated read-only Go binding around an Ethereum contract.
+type ConduitControllerCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ConduitControllerTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type ConduitControllerTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ConduitControllerFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type ConduitControllerFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ConduitControllerSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type ConduitControllerSession struct {
+	Contract     *ConduitController // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts      // Call options to use throughout this session
+	TransactOpts bind.TransactOpts  // Transaction auth options to use throughout this session
+}
+
+// ConduitControllerCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type ConduitControllerCallerSession struct {
+	Contract *ConduitControllerCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts            // Call options to use throughout this session
+}
+
+// ConduitControllerTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type ConduitControllerTransactorSession struct {
+	Contract     *ConduitControllerTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts            // Transaction auth options to use throughout this session
+}
+
+// ConduitControllerRaw is an auto generated low-level Go binding around an Ethereum contract.
+type ConduitControllerRaw struct {
+	Contract *ConduitController // Generic contract binding to access the raw methods on
+}
+
+// ConduitControllerCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type ConduitControllerCallerRaw struct {
+	Contract *ConduitControllerCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// ConduitControllerTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type ConduitControllerTransactorRaw struct {
+	Contract *ConduitControllerTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewConduitController creates a new instance of ConduitController, bound to a specific deployed contract.
+func NewConduitController(address common.Address, backend bind.ContractBackend) (*ConduitController, error) {
+	contract, err := bindConduitController(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &ConduitController{ConduitControllerCaller: ConduitControllerCaller{contract: contract}, ConduitControllerTransactor: ConduitControllerTransactor{contract: contract}, ConduitControllerFilterer: ConduitControllerFilterer{contract: contract}}, nil
+}
+
+// NewConduitControllerCaller creates a new read-only instance of ConduitController, bound to a specific deployed contract.
+func NewConduitControllerCaller(address common.Address, caller bind.ContractCaller) (*ConduitControllerCaller, error) {
+	contract, err := bindConduitController(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ConduitControllerCaller{contract: contract}, nil
+}
+
+// NewConduitControllerTransactor creates a new write-only instance of ConduitController, bound to a specific deployed contract.
+func NewConduitControllerTransactor(address common.Address, transactor bind.ContractTransactor) (*ConduitControllerTransactor, error) {
+	contract, err := bindConduitController(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ConduitControllerTransactor{contract: contract}, nil
+}
+
+// NewConduitControllerFilterer creates a new log filterer instance of ConduitController, bound to a specific deployed contract.
+func NewConduitControllerFilterer(address common.Address, filterer bind.ContractFilterer) (*ConduitControllerFilterer, error) {
+	contract, err := bindConduitController(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &ConduitControllerFilterer{contract: contract}, nil
+}
+
+// bindConduitController binds a generic wrapper to an already deployed contract.
+func bindConduitController(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := ConduitControllerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ConduitController *ConduitControllerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _ConduitController.Contract.ConduitControllerCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ConduitController *ConduitControllerRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ConduitController.Contract.ConduitControllerTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ConduitController *ConduitControllerRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ConduitController.Contract.ConduitControllerTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ConduitController *ConduitControllerCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _ConduitController.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ConduitController *ConduitControllerTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ConduitController.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ConduitController *ConduitControllerTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ConduitController.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetConduit is a free data retrieval call binding the contract method 0x6e9bfd9f.
+//
+// Solidity: function getConduit(bytes32 conduitKey) view returns(address conduit, bool exists)
+func (_ConduitController *ConduitControllerCaller) GetConduit(opts *bind.CallOpts, conduitKey [32]byte) (struct {
+	Conduit common.Address
+	Exists  bool
+}, error) {
+	var out []interface{}
+	err := _ConduitController.contract.Call(opts, &out, "getConduit", conduitKey)
+
+	outstruct := new(struct {
+		Conduit common.Address
+		Exists  bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Conduit = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.Exists = *abi.ConvertType(out[1], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// GetConduit is a free data retrieval call binding the contract method 0x6e9bfd9f.
+//
+// Solidity: function getConduit(bytes32 conduitKey) view returns(address conduit, bool exists)
+func (_ConduitController *ConduitControllerSession) GetConduit(conduitKey [32]byte) (struct {
+	Conduit common.Address
+	Exists  bool
+}, error) {
+	return _ConduitController.Contract.GetConduit(&_ConduitController.CallOpts, conduitKey)
+}
+
+// GetConduit is a free data retrieval call binding the contract method 0x6e9bfd9f.
+//
+// Solidity: function getConduit(bytes32 conduitKey) view returns(address conduit, bool exists)
+func (_ConduitController *ConduitControllerCallerSession) GetConduit(conduitKey [32]byte) (struct {
+	Conduit common.Address
+	Exists  bool
+}, error) {
+	return _ConduitController.Contract.GetConduit(&_ConduitController.CallOpts, conduitKey)
+}
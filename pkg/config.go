@@ -0,0 +1,610 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const defaultExpire = 24 * 60
+
+const defaultRepriceInterval = 60
+
+// Config describes the strategy the bot executes. It is loaded from a YAML or
+// JSON file so that strategies can be kept under version control.
+type Config struct {
+	Chain  string `json:"chain" yaml:"chain"`
+	Expire int    `json:"expire" yaml:"expire"`
+	Store  string `json:"store" yaml:"store"`
+	// RPC lists the endpoints of each chain, tried in order. Chains without
+	// an entry use the registry's endpoint.
+	RPC    map[string][]string `json:"rpc" yaml:"rpc"`
+	Wallet *WalletConfig       `json:"wallet" yaml:"wallet"`
+	// Wallets runs several wallets from one process, in place of Wallet.
+	Wallets      []*WalletConfig     `json:"wallets" yaml:"wallets"`
+	Transactions *TransactionsConfig `json:"transactions" yaml:"transactions"`
+	// Approvals is how missing conduit approvals are handled: prompt asks on
+	// the terminal, auto sends them and never only reports them.
+	Approvals   string             `json:"approvals" yaml:"approvals"`
+	Collections []CollectionConfig `json:"collections" yaml:"collections"`
+}
+
+// TransactionsConfig tunes how transactions are priced and followed. Fees are
+// in gwei, StuckAfter in seconds; zero values keep the defaults, except for
+// StuckAfter and MaxBumps where an explicit zero turns fee bumps off.
+type TransactionsConfig struct {
+	MaxFeePerGas   string `json:"max_fee_per_gas" yaml:"max_fee_per_gas"`
+	MaxPriorityFee string `json:"max_priority_fee" yaml:"max_priority_fee"`
+	Confirmations  uint64 `json:"confirmations" yaml:"confirmations"`
+	StuckAfter     *int   `json:"stuck_after" yaml:"stuck_after"`
+	BumpPercent    int64  `json:"bump_percent" yaml:"bump_percent"`
+	MaxBumps       *int   `json:"max_bumps" yaml:"max_bumps"`
+}
+
+// WalletConfig selects where the wallet key comes from, the PRIVATE_KEY
+// environment variable when unset. Keystore is a geth keystore file whose
+// passphrase is read from PassphraseFile or prompted for. External is the
+// endpoint of a Clef compatible signer. Address picks the signer's account and
+// is checked against the keystore.
+type WalletConfig struct {
+	Keystore       string `json:"keystore" yaml:"keystore"`
+	PassphraseFile string `json:"passphrase_file" yaml:"passphrase_file"`
+	External       string `json:"external" yaml:"external"`
+	Address        string `json:"address" yaml:"address"`
+}
+
+type CollectionConfig struct {
+	Contract string `json:"contract" yaml:"contract"`
+	Chain    string `json:"chain" yaml:"chain"`
+	// Wallets restricts the collection to these wallet addresses, every
+	// configured wallet trades it when empty.
+	Wallets      []string     `json:"wallets" yaml:"wallets"`
+	PaymentToken string       `json:"payment_token" yaml:"payment_token"`
+	Expire       int          `json:"expire" yaml:"expire"`
+	MaxSpend     string       `json:"max_spend" yaml:"max_spend"`
+	Sell         *SellConfig  `json:"sell" yaml:"sell"`
+	Buy          *BuyConfig   `json:"buy" yaml:"buy"`
+	Offer        *OfferConfig `json:"offer" yaml:"offer"`
+}
+
+type SellConfig struct {
+	Price       string            `json:"price" yaml:"price"`
+	NFTs        map[string]string `json:"nfts" yaml:"nfts"`
+	AcceptOffer string            `json:"accept_offer" yaml:"accept_offer"`
+	Reprice     *RepriceConfig    `json:"reprice" yaml:"reprice"`
+	Dutch       *DutchConfig      `json:"dutch" yaml:"dutch"`
+	// Private maps identifiers to the only address allowed to buy them.
+	Private map[string]string `json:"private" yaml:"private"`
+	Bundles []*BundleConfig   `json:"bundles" yaml:"bundles"`
+	// Quantity caps the units listed per ERC-1155 token, the whole balance is
+	// listed when zero. Prices are per unit.
+	Quantity int64 `json:"quantity" yaml:"quantity"`
+}
+
+// BundleConfig lists NFTs together for one price. NFTs are identifiers of the
+// collection, or contract:identifier for NFTs of other contracts.
+type BundleConfig struct {
+	Price string   `json:"price" yaml:"price"`
+	NFTs  []string `json:"nfts" yaml:"nfts"`
+}
+
+// DutchConfig lists at a unit price declining from StartPrice to EndPrice over
+// Duration minutes, the collection expire by default.
+type DutchConfig struct {
+	StartPrice string `json:"start_price" yaml:"start_price"`
+	EndPrice   string `json:"end_price" yaml:"end_price"`
+	Duration   int    `json:"duration" yaml:"duration"`
+}
+
+type RepriceConfig struct {
+	Tick     string `json:"tick" yaml:"tick"`
+	Margin   string `json:"margin" yaml:"margin"`
+	MinPrice string `json:"min_price" yaml:"min_price"`
+	// Interval is the number of seconds between two repricing rounds.
+	Interval int `json:"interval" yaml:"interval"`
+}
+
+type BuyConfig struct {
+	MaxPrice string `json:"max_price" yaml:"max_price"`
+	Limit    int    `json:"limit" yaml:"limit"`
+	Sweep    bool   `json:"sweep" yaml:"sweep"`
+}
+
+type OfferConfig struct {
+	NFTs       map[string]string      `json:"nfts" yaml:"nfts"`
+	Collection *CriteriaOfferConfig   `json:"collection" yaml:"collection"`
+	Traits     []*CriteriaOfferConfig `json:"traits" yaml:"traits"`
+}
+
+type CriteriaOfferConfig struct {
+	Type     string `json:"type" yaml:"type"`
+	Value    string `json:"value" yaml:"value"`
+	Price    string `json:"price" yaml:"price"`
+	Quantity int    `json:"quantity" yaml:"quantity"`
+}
+
+func LoadConfig(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(raw, &cfg)
+	default:
+		err = yaml.Unmarshal(raw, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	cfg.applyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (c *Config) applyDefaults() {
+	if c.Chain == "" {
+		c.Chain = "ethereum"
+	}
+	if c.Expire == 0 {
+		c.Expire = defaultExpire
+	}
+	for i := range c.Collections {
+		col := &c.Collections[i]
+		if col.Chain == "" {
+			col.Chain = c.Chain
+		}
+		if col.Expire == 0 {
+			col.Expire = c.Expire
+		}
+		if col.Buy != nil && col.Buy.Limit == 0 {
+			col.Buy.Limit = 1
+		}
+		if col.Sell != nil && col.Sell.Dutch != nil && col.Sell.Dutch.Duration == 0 {
+			col.Sell.Dutch.Duration = col.Expire
+		}
+		if col.Sell != nil && col.Sell.Reprice != nil && col.Sell.Reprice.Interval == 0 {
+			col.Sell.Reprice.Interval = defaultRepriceInterval
+		}
+		if col.Offer != nil {
+			for _, offer := range append(col.Offer.Traits, col.Offer.Collection) {
+				if offer != nil && offer.Quantity == 0 {
+					offer.Quantity = 1
+				}
+			}
+		}
+	}
+}
+
+func (c *Config) Validate() error {
+	if len(c.Collections) == 0 {
+		return errors.New("config: no collections configured")
+	}
+	if err := c.validateRPC(); err != nil {
+		return fmt.Errorf("config: rpc: %w", err)
+	}
+	if c.Wallet != nil && len(c.Wallets) > 0 {
+		return errors.New("config: wallet and wallets are exclusive")
+	}
+	for i, wallet := range c.walletConfigs() {
+		if err := wallet.validate(); err != nil {
+			return fmt.Errorf("config: wallets[%d]: %w", i, err)
+		}
+	}
+	if c.Transactions != nil {
+		if err := c.Transactions.validate(); err != nil {
+			return fmt.Errorf("config: transactions: %w", err)
+		}
+	}
+	if _, ok := approvalModes[c.Approvals]; !ok {
+		return fmt.Errorf("config: unknown approvals mode %q", c.Approvals)
+	}
+	for i := range c.Collections {
+		if err := c.Collections[i].validate(); err != nil {
+			return fmt.Errorf("config: collections[%d]: %w", i, err)
+		}
+		// the bid exposure guard sums the open offers recorded in the store
+		if c.Collections[i].Offer != nil && c.Store == "" {
+			return fmt.Errorf("config: collections[%d]: offers need a store", i)
+		}
+	}
+	return nil
+}
+
+var approvalModes = map[string]ConfirmFunc{
+	"":       PromptApproval,
+	"prompt": PromptApproval,
+	"auto":   AutoApprove,
+	"never":  nil,
+}
+
+// approvalConfirm returns the confirm function of the approvals mode.
+func (c *Config) approvalConfirm() ConfirmFunc {
+	return approvalModes[c.Approvals]
+}
+
+func (c *Config) walletConfigs() []*WalletConfig {
+	if c.Wallet != nil {
+		return []*WalletConfig{c.Wallet}
+	}
+	return c.Wallets
+}
+
+func (w *WalletConfig) validate() error {
+	if (w.Keystore == "") == (w.External == "") {
+		return errors.New("one of keystore or external required")
+	}
+	if w.PassphraseFile != "" && w.Keystore == "" {
+		return errors.New("passphrase_file needs a keystore")
+	}
+	if w.Address != "" && !common.IsHexAddress(w.Address) {
+		return fmt.Errorf("invalid address %q", w.Address)
+	}
+	return nil
+}
+
+func (t *TransactionsConfig) validate() error {
+	for name, value := range map[string]string{"max_fee_per_gas": t.MaxFeePerGas, "max_priority_fee": t.MaxPriorityFee} {
+		if value == "" {
+			continue
+		}
+		if _, err := parsePositive(value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if (t.StuckAfter != nil && *t.StuckAfter < 0) || t.BumpPercent < 0 || (t.MaxBumps != nil && *t.MaxBumps < 0) {
+		return errors.New("stuck_after, bump_percent and max_bumps must be positive")
+	}
+	if t.BumpPercent > 0 && t.BumpPercent < 10 {
+		return errors.New("bump_percent must be at least 10, nodes reject smaller replacements")
+	}
+	return nil
+}
+
+// txConfig returns the TxConfig of t, the default one when t is nil.
+func (t *TransactionsConfig) txConfig() TxConfig {
+	config := DefaultTxConfig()
+	if t == nil {
+		return config
+	}
+	if t.MaxFeePerGas != "" {
+		config.MaxFeePerGas = gweiToWei(t.MaxFeePerGas)
+	}
+	if t.MaxPriorityFee != "" {
+		config.MaxTipCap = gweiToWei(t.MaxPriorityFee)
+	}
+	if t.Confirmations > 0 {
+		config.Confirmations = t.Confirmations
+	}
+	if t.StuckAfter != nil {
+		config.StuckAfter = time.Duration(*t.StuckAfter) * time.Second
+	}
+	if t.BumpPercent > 0 {
+		config.BumpPercent = t.BumpPercent
+	}
+	if t.MaxBumps != nil {
+		config.MaxBumps = *t.MaxBumps
+	}
+	return config
+}
+
+func gweiToWei(gwei string) *big.Int {
+	return decimal.RequireFromString(gwei).Shift(9).BigInt()
+}
+
+// validateRPC checks the endpoints and keys them by the chain's registry name.
+func (c *Config) validateRPC() error {
+	endpoints := make(map[string][]string, len(c.RPC))
+	for name, urls := range c.RPC {
+		network, err := LookupChain(name)
+		if err != nil {
+			return err
+		}
+		if len(urls) == 0 {
+			return fmt.Errorf("%s: no endpoint", name)
+		}
+		for _, raw := range urls {
+			if err := validateRPCURL(raw); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		endpoints[network.Name] = append(endpoints[network.Name], urls...)
+	}
+	c.RPC = endpoints
+	return nil
+}
+
+// validateRPCURL accepts HTTP and WebSocket URLs and IPC socket paths.
+func validateRPCURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint %q", raw)
+	}
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+		if u.Host == "" {
+			return fmt.Errorf("invalid endpoint %q", raw)
+		}
+	case "":
+		if u.Path == "" {
+			return errors.New("empty endpoint")
+		}
+	default:
+		return fmt.Errorf("unsupported endpoint scheme %q", u.Scheme)
+	}
+	return nil
+}
+
+func (c *CollectionConfig) validate() error {
+	if !common.IsHexAddress(c.Contract) {
+		return fmt.Errorf("invalid contract address %q", c.Contract)
+	}
+	network, err := LookupChain(c.Chain)
+	if err != nil {
+		return err
+	}
+	c.Chain = network.Name
+	for _, wallet := range c.Wallets {
+		if !common.IsHexAddress(wallet) {
+			return fmt.Errorf("invalid wallet address %q", wallet)
+		}
+	}
+	if c.PaymentToken != "" && !common.IsHexAddress(c.PaymentToken) {
+		return fmt.Errorf("invalid payment token %q", c.PaymentToken)
+	}
+	if c.Expire < 0 {
+		return errors.New("expire must be positive")
+	}
+	if c.Sell == nil && c.Buy == nil && c.Offer == nil {
+		return errors.New("no sell, buy or offer rules configured")
+	}
+	if c.MaxSpend != "" {
+		if _, err := parsePositive(c.MaxSpend); err != nil {
+			return fmt.Errorf("max_spend: %w", err)
+		}
+	}
+	if c.Sell != nil {
+		if c.Sell.Price == "" && len(c.Sell.NFTs) == 0 && c.Sell.AcceptOffer == "" && c.Sell.Reprice == nil && c.Sell.Dutch == nil && len(c.Sell.Bundles) == 0 {
+			return errors.New("sell: price, nfts, accept_offer, reprice, dutch or bundles required")
+		}
+		for i, bundle := range c.Sell.Bundles {
+			if _, err := parsePositive(bundle.Price); err != nil {
+				return fmt.Errorf("sell.bundles[%d].price: %w", i, err)
+			}
+			if _, err := bundle.Items(c.Contract); err != nil {
+				return fmt.Errorf("sell.bundles[%d]: %w", i, err)
+			}
+		}
+		if c.Sell.Dutch != nil {
+			if err := c.Sell.Dutch.validate(); err != nil {
+				return fmt.Errorf("sell.dutch: %w", err)
+			}
+		}
+		if c.Sell.Reprice != nil {
+			if _, err := c.Sell.Reprice.rule(c.Sell.Price); err != nil {
+				return fmt.Errorf("sell.reprice: %w", err)
+			}
+			if c.Sell.Reprice.Interval < 0 {
+				return errors.New("sell.reprice.interval must be positive")
+			}
+		}
+		for identifier, taker := range c.Sell.Private {
+			if _, ok := c.Sell.PriceFor(identifier); !ok {
+				return fmt.Errorf("sell.private.%s: no price", identifier)
+			}
+			if _, err := parseTaker(taker); err != nil {
+				return fmt.Errorf("sell.private.%s: %w", identifier, err)
+			}
+		}
+		if c.Sell.Quantity < 0 {
+			return errors.New("sell.quantity must not be negative")
+		}
+		if c.Sell.AcceptOffer != "" {
+			if _, err := parsePositive(c.Sell.AcceptOffer); err != nil {
+				return fmt.Errorf("sell.accept_offer: %w", err)
+			}
+		}
+		if c.Sell.Price != "" {
+			if _, err := parsePositive(c.Sell.Price); err != nil {
+				return fmt.Errorf("sell.price: %w", err)
+			}
+		}
+		for identifier, price := range c.Sell.NFTs {
+			if _, err := parsePositive(price); err != nil {
+				return fmt.Errorf("sell.nfts[%s]: %w", identifier, err)
+			}
+		}
+	}
+	if c.Buy != nil {
+		if _, err := parsePositive(c.Buy.MaxPrice); err != nil {
+			return fmt.Errorf("buy.max_price: %w", err)
+		}
+		if c.Buy.Limit < 0 {
+			return errors.New("buy.limit must be positive")
+		}
+	}
+	if c.Offer != nil {
+		if len(c.Offer.NFTs) == 0 && c.Offer.Collection == nil && len(c.Offer.Traits) == 0 {
+			return errors.New("offer: nfts, collection or traits required")
+		}
+		if c.Offer.Collection != nil {
+			if err := c.Offer.Collection.validate(false); err != nil {
+				return fmt.Errorf("offer.collection: %w", err)
+			}
+		}
+		for i, trait := range c.Offer.Traits {
+			if err := trait.validate(true); err != nil {
+				return fmt.Errorf("offer.traits[%d]: %w", i, err)
+			}
+		}
+		for identifier, price := range c.Offer.NFTs {
+			if _, err := parsePositive(price); err != nil {
+				return fmt.Errorf("offer.nfts[%s]: %w", identifier, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *CriteriaOfferConfig) validate(trait bool) error {
+	if trait && (c.Type == "" || c.Value == "") {
+		return errors.New("type and value required")
+	}
+	if _, err := parsePositive(c.Price); err != nil {
+		return fmt.Errorf("price: %w", err)
+	}
+	if c.Quantity < 0 {
+		return errors.New("quantity must be positive")
+	}
+	return nil
+}
+
+// PriceFor returns the configured list price for identifier, preferring a
+// per-NFT rule over the collection-wide price.
+func (s *SellConfig) PriceFor(identifier string) (string, bool) {
+	if price, ok := s.NFTs[identifier]; ok {
+		return price, true
+	}
+	if s.Price != "" {
+		return s.Price, true
+	}
+	return "", false
+}
+
+// Items returns the NFTs of the bundle, contract being the collection's.
+func (b *BundleConfig) Items(contract string) ([]*NFT, error) {
+	if len(b.NFTs) < 2 {
+		return nil, errors.New("a bundle needs at least two nfts")
+	}
+	nfts := make([]*NFT, 0, len(b.NFTs))
+	for _, item := range b.NFTs {
+		nft := &NFT{Contract: contract, Identifier: item}
+		if i := strings.Index(item, ":"); i >= 0 {
+			nft.Contract, nft.Identifier = item[:i], item[i+1:]
+			if !common.IsHexAddress(nft.Contract) {
+				return nil, fmt.Errorf("invalid contract address %q", nft.Contract)
+			}
+		}
+		if _, err := nft.identifier(); err != nil {
+			return nil, err
+		}
+		nfts = append(nfts, nft)
+	}
+	return nfts, nil
+}
+
+// bundled reports whether the NFT identifier of contract is part of a bundle.
+func (s *SellConfig) bundled(contract, identifier string) bool {
+	for _, bundle := range s.Bundles {
+		nfts, _ := bundle.Items(contract)
+		for _, nft := range nfts {
+			if strings.EqualFold(nft.Contract, contract) && nft.Identifier == identifier {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (d *DutchConfig) validate() error {
+	start, err := parsePositive(d.StartPrice)
+	if err != nil {
+		return fmt.Errorf("start_price: %w", err)
+	}
+	end, err := parsePositive(d.EndPrice)
+	if err != nil {
+		return fmt.Errorf("end_price: %w", err)
+	}
+	if !end.LessThan(start) {
+		return errors.New("end_price must be below start_price")
+	}
+	if d.Duration < 0 {
+		return errors.New("duration must be positive")
+	}
+	return nil
+}
+
+// QuantityFor returns the number of units of nft to list.
+func (s *SellConfig) QuantityFor(nft *NFT) int64 {
+	if nft.nftType() != 3 || nft.Balance == nil || !nft.Balance.IsInt64() {
+		return 1
+	}
+	quantity := nft.Balance.Int64()
+	if s.Quantity > 0 && s.Quantity < quantity {
+		quantity = s.Quantity
+	}
+	return quantity
+}
+
+// repriced reports whether identifier is priced by the repricer rather than at
+// a fixed price. Per-NFT prices always stay fixed.
+func (s *SellConfig) repriced(identifier string) bool {
+	return s.Reprice != nil && !s.fixed(identifier)
+}
+
+// auctioned reports whether identifier is sold in a Dutch auction. Per-NFT
+// prices and repricing take precedence.
+func (s *SellConfig) auctioned(identifier string) bool {
+	return s.Dutch != nil && s.Reprice == nil && !s.fixed(identifier)
+}
+
+// fixed reports whether identifier has its own price or is sold privately.
+func (s *SellConfig) fixed(identifier string) bool {
+	_, priced := s.NFTs[identifier]
+	_, private := s.Private[identifier]
+	return priced || private
+}
+
+// tradedBy reports whether wallet may trade the collection.
+func (c *CollectionConfig) tradedBy(wallet common.Address) bool {
+	if len(c.Wallets) == 0 {
+		return true
+	}
+	for _, address := range c.Wallets {
+		if common.HexToAddress(address) == wallet {
+			return true
+		}
+	}
+	return false
+}
+
+// repriceInterval is the shortest repricing interval of all collections, zero
+// when no collection is repriced.
+func (c *Config) repriceInterval() time.Duration {
+	var interval time.Duration
+	for _, col := range c.Collections {
+		if col.Sell == nil || col.Sell.Reprice == nil {
+			continue
+		}
+		d := time.Duration(col.Sell.Reprice.Interval) * time.Second
+		if interval == 0 || d < interval {
+			interval = d
+		}
+	}
+	return interval
+}
+
+func parsePositive(value string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if !d.IsPositive() {
+		return decimal.Zero, fmt.Errorf("%s must be greater than zero", value)
+	}
+	return d, nil
+}
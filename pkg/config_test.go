@@ -0,0 +1,207 @@
+package pkg
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, "bot.yaml", `
+chain: sepolia
+collections:
+  - contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24"
+    max_spend: "1"
+    sell:
+      price: "0.2"
+      nfts:
+        "7": "0.5"
+    buy:
+      max_price: "0.1"
+`)
+	cfg, err := LoadConfig(path)
+	require.Nil(t, err)
+	require.Len(t, cfg.Collections, 1)
+
+	col := cfg.Collections[0]
+	require.Equal(t, "sepolia", col.Chain)
+	require.Equal(t, defaultExpire, col.Expire)
+	require.Equal(t, 1, col.Buy.Limit)
+
+	price, ok := col.Sell.PriceFor("7")
+	require.True(t, ok)
+	require.Equal(t, "0.5", price)
+	price, ok = col.Sell.PriceFor("8")
+	require.True(t, ok)
+	require.Equal(t, "0.2", price)
+}
+
+func TestLoadConfig_CriteriaOffers(t *testing.T) {
+	path := writeConfig(t, "bot.yaml", `
+store: orders.db
+collections:
+  - contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24"
+    offer:
+      collection: {price: "0.01"}
+      traits:
+        - {type: Hat, value: Crown, price: "0.02", quantity: 3}
+`)
+	cfg, err := LoadConfig(path)
+	require.Nil(t, err)
+
+	offer := cfg.Collections[0].Offer
+	require.Equal(t, 1, offer.Collection.Quantity)
+	require.Equal(t, 3, offer.Traits[0].Quantity)
+}
+
+func TestLoadConfig_JSON(t *testing.T) {
+	path := writeConfig(t, "bot.json", `{
+	"collections": [{"contract": "0x300b105942d6d181cdfe8199fd48eb09d26efd24", "expire": 30, "sell": {"nfts": {"1": "0.3"}}}]
+}`)
+	cfg, err := LoadConfig(path)
+	require.Nil(t, err)
+	require.Equal(t, "ethereum", cfg.Collections[0].Chain)
+	require.Equal(t, 30, cfg.Collections[0].Expire)
+
+	_, ok := cfg.Collections[0].Sell.PriceFor("2")
+	require.False(t, ok)
+}
+
+func TestLoadConfig_ChainAlias(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "bot.yaml", `
+chain: polygon
+collections:
+  - contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24"
+    sell: {price: "1"}
+  - contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24"
+    chain: base
+    sell: {price: "1"}
+`))
+	require.Nil(t, err)
+	require.Equal(t, "matic", cfg.Collections[0].Chain)
+	require.Equal(t, "base", cfg.Collections[1].Chain)
+}
+
+func TestLoadConfig_RPC(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "bot.yaml", `
+rpc:
+  polygon:
+    - "wss://polygon.example/ws"
+    - "/var/run/bor.ipc"
+  ethereum: ["https://mainnet.infura.io/v3/${INFURA_KEY}"]
+collections:
+  - contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24"
+    sell: {price: "1"}
+`))
+	require.Nil(t, err)
+	require.Equal(t, []string{"wss://polygon.example/ws", "/var/run/bor.ipc"}, cfg.RPC["matic"])
+	require.Len(t, cfg.RPC["ethereum"], 1)
+}
+
+func TestLoadConfig_Transactions(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "bot.yaml", `
+transactions:
+  max_fee_per_gas: "80"
+  max_priority_fee: "1.5"
+  confirmations: 3
+  stuck_after: 60
+collections:
+  - contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24"
+    sell: {price: "1"}
+`))
+	require.Nil(t, err)
+	config := cfg.Transactions.txConfig()
+	require.Equal(t, "80000000000", config.MaxFeePerGas.String())
+	require.Equal(t, "1500000000", config.MaxTipCap.String())
+	require.Equal(t, uint64(3), config.Confirmations)
+	require.Equal(t, time.Minute, config.StuckAfter)
+	require.Equal(t, DefaultTxConfig().BumpPercent, config.BumpPercent)
+	require.Equal(t, DefaultTxConfig().MaxBumps, config.MaxBumps)
+	require.Nil(t, (*TransactionsConfig)(nil).txConfig().MaxFeePerGas)
+
+	// explicit zeros turn fee bumps off
+	cfg, err = LoadConfig(writeConfig(t, "bot.yaml", `
+transactions: {stuck_after: 0, max_bumps: 0}
+collections:
+  - contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24"
+    sell: {price: "1"}
+`))
+	require.Nil(t, err)
+	config = cfg.Transactions.txConfig()
+	require.Zero(t, config.StuckAfter)
+	require.Zero(t, config.MaxBumps)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cases := map[string]string{
+		"no collections":     `chain: sepolia`,
+		"bad contract":       `collections: [{contract: "0x1", sell: {price: "1"}}]`,
+		"bad chain":          `collections: [{contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", chain: "moon", sell: {price: "1"}}]`,
+		"no rules":           `collections: [{contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24"}]`,
+		"zero price":         `collections: [{contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", sell: {price: "0"}}]`,
+		"missing maxprice":   `collections: [{contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", buy: {limit: 2}}]`,
+		"empty offer":        `collections: [{contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", offer: {}}]`,
+		"trait no value":     `collections: [{contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", offer: {traits: [{type: Hat, price: "1"}]}}]`,
+		"offer no store":     `collections: [{contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", offer: {collection: {price: "1"}}}]`,
+		"dutch rising":       `collections: [{contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", sell: {dutch: {start_price: "1", end_price: "2"}}}]`,
+		"private taker":      `collections: [{contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", sell: {price: "1", private: {"1": "0x1234"}}}]`,
+		"reprice no tick":    `collections: [{contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", sell: {reprice: {margin: "0.1"}}}]`,
+		"rpc chain":          "rpc: {moon: [\"https://rpc.moon\"]}\ncollections: [{contract: \"0x300b105942d6d181cdfe8199fd48eb09d26efd24\", sell: {price: \"1\"}}]",
+		"wallet both":        "wallet: {keystore: key.json, external: \"http://localhost:8550\"}\ncollections: [{contract: \"0x300b105942d6d181cdfe8199fd48eb09d26efd24\", sell: {price: \"1\"}}]",
+		"wallet address":     "wallet: {external: \"http://localhost:8550\", address: \"0x12\"}\ncollections: [{contract: \"0x300b105942d6d181cdfe8199fd48eb09d26efd24\", sell: {price: \"1\"}}]",
+		"wallet and wallets": "wallet: {external: \"http://localhost:8550\"}\nwallets: [{keystore: key.json}]\ncollections: [{contract: \"0x300b105942d6d181cdfe8199fd48eb09d26efd24\", sell: {price: \"1\"}}]",
+		"collection wallet":  `collections: [{contract: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", wallets: ["0x12"], sell: {price: "1"}}]`,
+		"rpc scheme":         "rpc: {ethereum: [\"ftp://node\"]}\ncollections: [{contract: \"0x300b105942d6d181cdfe8199fd48eb09d26efd24\", sell: {price: \"1\"}}]",
+		"small bump":         "transactions: {bump_percent: 5}\ncollections: [{contract: \"0x300b105942d6d181cdfe8199fd48eb09d26efd24\", sell: {price: \"1\"}}]",
+		"zero fee cap":       "transactions: {max_fee_per_gas: \"0\"}\ncollections: [{contract: \"0x300b105942d6d181cdfe8199fd48eb09d26efd24\", sell: {price: \"1\"}}]",
+		"approvals mode":     "approvals: always\ncollections: [{contract: \"0x300b105942d6d181cdfe8199fd48eb09d26efd24\", sell: {price: \"1\"}}]",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, "bot.yaml", content))
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestSelectListings(t *testing.T) {
+	listing := func(hash, value string) BestListingResp {
+		var l BestListingResp
+		l.OrderHash = hash
+		l.Price.Current.Value = value
+		l.Price.Current.Decimals = 18
+		return l
+	}
+	selected, total := selectListings([]BestListingResp{
+		listing("a", "80000000000000000"),
+		listing("b", "200000000000000000"),
+		listing("c", "90000000000000000"),
+		listing("d", "100000000000000000"),
+	}, decimal.RequireFromString("0.1"), decimal.RequireFromString("0.25"))
+
+	require.Len(t, selected, 2)
+	require.Equal(t, "a", selected[0].OrderHash)
+	require.Equal(t, "c", selected[1].OrderHash)
+	require.Equal(t, "0.17", total.String())
+}
+
+func TestSellConfig_QuantityFor(t *testing.T) {
+	sell := &SellConfig{Price: "1"}
+	edition := &NFT{TokenStandard: NftType1155, Balance: big.NewInt(12)}
+	require.Equal(t, int64(12), sell.QuantityFor(edition))
+	require.Equal(t, int64(1), sell.QuantityFor(&NFT{TokenStandard: NftType721, Balance: big.NewInt(1)}))
+	require.Equal(t, int64(0), sell.QuantityFor(&NFT{TokenStandard: NftType1155, Balance: new(big.Int)}))
+
+	sell.Quantity = 5
+	require.Equal(t, int64(5), sell.QuantityFor(edition))
+}
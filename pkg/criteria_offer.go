@@ -0,0 +1,220 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// CreateCollectionOffer bids price WETH for each of quantity items of any token
+// in the collection.
+func (a *Account) CreateCollectionOffer(ctx context.Context, price string, quantity, expire int) (*CriteriaOfferResp, error) {
+	collection, err := a.GetCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !collection.CollectionOffersEnabled {
+		return nil, fmt.Errorf("collection offers are disabled for %s", collection.Collection)
+	}
+
+	var criteria offerCriteria
+	criteria.Collection.Slug = collection.Collection
+	return a.createCriteriaOffer(ctx, SideCollectionOffer, collection, criteria, price, quantity, expire)
+}
+
+// CreateTraitOffer bids price WETH for each of quantity items of the collection
+// carrying the trait traitType: traitValue.
+func (a *Account) CreateTraitOffer(ctx context.Context, traitType, traitValue, price string, quantity, expire int) (*CriteriaOfferResp, error) {
+	if traitType == "" || traitValue == "" {
+		return nil, errors.New("trait type and value are required")
+	}
+	collection, err := a.GetCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !collection.TraitOffersEnabled {
+		return nil, fmt.Errorf("trait offers are disabled for %s", collection.Collection)
+	}
+
+	var criteria offerCriteria
+	criteria.Collection.Slug = collection.Collection
+	criteria.Trait = &offerTrait{Type: traitType, Value: traitValue}
+	return a.createCriteriaOffer(ctx, SideTraitOffer, collection, criteria, price, quantity, expire)
+}
+
+func (a *Account) createCriteriaOffer(ctx context.Context, side string, collection *CollectionResp, criteria offerCriteria, price string, quantity, expire int) (*CriteriaOfferResp, error) {
+	startTime := time.Now().Local().Unix()
+	endTime := time.Now().Local().Add(time.Duration(expire) * time.Minute).Unix()
+
+	offer, offerPrice, err := a.wethOfferItem(ctx, price, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	build, err := a.buildOffer(ctx, criteria, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	considerations := make([]ConsiderationItem, 0)
+	for _, item := range build.PartialParameters.Consideration {
+		if item.ItemType != 4 && item.ItemType != 5 {
+			return nil, fmt.Errorf("unexpected item type %d in criteria consideration", item.ItemType)
+		}
+		consideration, err := item.toConsiderationItem()
+		if err != nil {
+			return nil, err
+		}
+		considerations = append(considerations, consideration)
+	}
+	// fees are computed per item and scaled so that every partial fill pays
+	// exact amounts
+	units := decimal.NewFromInt(int64(quantity))
+	fees, _ := collection.feeConsiderations(offerPrice.Div(units), offer.ItemType, offer.Token)
+	for i := range fees {
+		fees[i].StartAmount = new(big.Int).Mul(fees[i].StartAmount, units.BigInt())
+		fees[i].EndAmount = new(big.Int).Mul(fees[i].EndAmount, units.BigInt())
+	}
+	considerations = append(considerations, fees...)
+
+	counter, err := a.seaportInstance.GetCounter(nil, a.WalletAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	param := OrderParameters{
+		Offerer:                         a.WalletAddress().Hex(),
+		Zone:                            build.PartialParameters.Zone,
+		ZoneHash:                        build.PartialParameters.ZoneHash,
+		StartTime:                       startTime,
+		EndTime:                         endTime,
+		OrderType:                       criteriaOrderType(build.PartialParameters.Zone, quantity),
+		Salt:                            fixedSalt(),
+		ConduitKey:                      a.chain.ConduitKey,
+		Offer:                           []OfferItem{offer},
+		Consideration:                   considerations,
+		TotalOriginalConsiderationItems: len(considerations),
+		Counter:                         counter,
+	}
+	if param.Zone == "" {
+		param.Zone = zeroAddress().Hex()
+	}
+	if param.ZoneHash == "" {
+		param.ZoneHash = zero32BytesHexString()
+	}
+
+	if err := a.checkBidExposure(ctx, param.Offer, false); err != nil {
+		return nil, err
+	}
+	if err := a.ensureApprovals(ctx, param.Offer); err != nil {
+		return nil, err
+	}
+	if err := a.checkBidExposure(ctx, param.Offer, true); err != nil {
+		return nil, err
+	}
+	data, err := param.signTypedData(a)
+	if err != nil {
+		return nil, err
+	}
+	orderHash, err := a.trackOrder(side, data, price)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"protocol_data": map[string]interface{}{
+			"parameters": data.Parameters,
+			"signature":  data.Signature,
+		},
+		"criteria":         build.Criteria,
+		"protocol_address": data.ProtocolAddress,
+	}
+	output, err := a.postCriteriaOffer(ctx, orderHash, body)
+	if err != nil {
+		return nil, err
+	}
+	a.markOrder(orderHash, OrderActive)
+	log.Println(output)
+	return output, nil
+}
+
+// criteriaOrderType restricts the offer to OpenSea's zone when the build
+// returned one, and lets offers for several items be filled one at a time.
+func criteriaOrderType(zone string, quantity int) uint8 {
+	orderType := uint8(0) // FULL_OPEN
+	if quantity > 1 {
+		orderType = 1 // PARTIAL_OPEN
+	}
+	if zone != "" && !strings.EqualFold(zone, zeroAddress().Hex()) {
+		orderType += 2 // FULL_RESTRICTED or PARTIAL_RESTRICTED
+	}
+	return orderType
+}
+
+// postCriteriaOffer submits a signed collection or trait offer to OpenSea and
+// checks the order hash it reports against orderHash.
+func (a *Account) postCriteriaOffer(ctx context.Context, orderHash string, body map[string]interface{}) (*CriteriaOfferResp, error) {
+	req := a.newRequest().
+		Post(fmt.Sprintf("%s/api/v2/offers", getOpenSeaAPI(a.contract.Chain))).Send(body)
+	log.Println(req.AsCurlCommand())
+
+	var output *CriteriaOfferResp
+	resp, respBody, errs := req.EndStruct(&output)
+	if resp != nil && resp.StatusCode >= 300 {
+		a.markOrder(orderHash, OrderRejected)
+		return nil, fmt.Errorf("%w: %s: %s", ErrOrderRejected, resp.Status, respBody)
+	}
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	if err := checkOrderHash(orderHash, output.OrderHash); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// buildOffer asks OpenSea for the criteria-based consideration and zone of a
+// collection or trait offer.
+func (a *Account) buildOffer(ctx context.Context, criteria offerCriteria, quantity int) (*BuildOfferResp, error) {
+	body := map[string]interface{}{
+		"offerer":                  a.WalletAddress().Hex(),
+		"quantity":                 quantity,
+		"criteria":                 criteria,
+		"protocol_address":         a.chain.SeaportAddress,
+		"offer_protection_enabled": true,
+	}
+
+	var data *BuildOfferResp
+	req := a.newRequest().
+		Post(fmt.Sprintf("%s/api/v2/offers/build", getOpenSeaAPI(a.contract.Chain))).Send(body)
+	log.Println(req.AsCurlCommand())
+	resp, _, errs := req.EndStruct(&data)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	log.Println(resp)
+	if len(data.PartialParameters.Consideration) == 0 {
+		return nil, errors.New("build offer returned no consideration")
+	}
+	return data, nil
+}
+
+func (i *apiConsiderationItem) toConsiderationItem() (ConsiderationItem, error) {
+	identifier, start, end, err := parseItemAmounts(i.IdentifierOrCriteria, i.StartAmount, i.EndAmount)
+	if err != nil {
+		return ConsiderationItem{}, err
+	}
+	return ConsiderationItem{
+		ItemType:             uint8(i.ItemType),
+		Token:                i.Token,
+		IdentifierOrCriteria: identifier,
+		StartAmount:          start,
+		EndAmount:            end,
+		Recipient:            i.Recipient,
+	}, nil
+}
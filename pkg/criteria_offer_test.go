@@ -0,0 +1,48 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCriteriaOrderType(t *testing.T) {
+	zone := "0x000056F7000000EcE9003ca63978907a00FFD100"
+	require.Equal(t, uint8(0), criteriaOrderType("", 1))
+	require.Equal(t, uint8(1), criteriaOrderType(zeroAddress().Hex(), 3))
+	require.Equal(t, uint8(2), criteriaOrderType(zone, 1))
+	require.Equal(t, uint8(3), criteriaOrderType(zone, 3))
+}
+
+func TestPostCriteriaOffer_Rejected(t *testing.T) {
+	client := apiClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errors": ["Outstanding offer amount exceeds balance"]}`))
+	})
+	account := testAccount(t, nil, client)
+	store, err := OpenOrderStore(filepath.Join(t.TempDir(), "store"))
+	require.Nil(t, err)
+	defer store.Close()
+	account.SetOrderStore(store)
+	offer := []OfferItem{{ItemType: 1, Token: testWETH.Hex(), StartAmount: big.NewInt(2), EndAmount: big.NewInt(2)}}
+	require.Nil(t, store.Put(&StoredOrder{OrderHash: "0x01", Chain: "sepolia", Side: SideCollectionOffer, Status: OrderCreated,
+		ExpireAt: time.Now().Add(time.Hour).Unix(), Parameters: OrderParameters{Offerer: account.WalletAddress().Hex(), Offer: offer}}))
+
+	_, err = account.postCriteriaOffer(context.Background(), "0x01", map[string]interface{}{})
+	require.True(t, errors.Is(err, ErrOrderRejected))
+	require.True(t, strings.Contains(err.Error(), "exceeds balance"))
+
+	// a refused offer no longer counts against the wallet
+	order, err := store.Get("0x01")
+	require.Nil(t, err)
+	require.Equal(t, OrderRejected, order.Status)
+	exposure, err := account.BidExposure(testWETH)
+	require.Nil(t, err)
+	require.Zero(t, exposure.Sign())
+}
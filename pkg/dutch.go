@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// CreateDutchListing lists quantity units of nft in a Dutch auction whose unit
+// price declines linearly from startPrice to endPrice over duration minutes, at
+// which point the listing expires.
+func (a *Account) CreateDutchListing(ctx context.Context, nft *NFT, quantity int64, startPrice, endPrice string, duration int) (*CreateListingResp, error) {
+	return a.createListing(ctx, nft, quantity, startPrice, endPrice, duration, zeroAddress())
+}
+
+type PricePoint struct {
+	At time.Time
+	// Price is what a buyer pays at At, Proceeds what the offerer receives.
+	Price    decimal.Decimal
+	Proceeds decimal.Decimal
+}
+
+// PriceCurve samples the price of the order at steps+1 evenly spaced instants
+// between its start and end time, the way Seaport computes it when filled.
+// Amounts are shifted by decimals into token units.
+func (p *OrderParameters) PriceCurve(decimals, steps int) []PricePoint {
+	if steps <= 0 {
+		steps = 1
+	}
+	points := make([]PricePoint, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		at := p.StartTime + (p.EndTime-p.StartTime)*int64(i)/int64(steps)
+		price, proceeds := new(big.Int), new(big.Int)
+		for _, item := range p.Consideration {
+			if item.ItemType > 1 {
+				continue
+			}
+			amount := currentAmount(item.StartAmount, item.EndAmount, p.StartTime, p.EndTime, at)
+			price.Add(price, amount)
+			if strings.EqualFold(item.Recipient, p.Offerer) {
+				proceeds.Add(proceeds, amount)
+			}
+		}
+		points = append(points, PricePoint{
+			At:       time.Unix(at, 0),
+			Price:    decimal.NewFromBigInt(price, int32(-decimals)),
+			Proceeds: decimal.NewFromBigInt(proceeds, int32(-decimals)),
+		})
+	}
+	return points
+}
+
+// currentAmount interpolates a consideration amount at time at, rounding up as
+// Seaport does for consideration items.
+func currentAmount(start, end *big.Int, startTime, endTime, at int64) *big.Int {
+	start, end = bigOrZero(start), bigOrZero(end)
+	if start.Cmp(end) == 0 || endTime <= startTime {
+		return new(big.Int).Set(start)
+	}
+	if at < startTime {
+		at = startTime
+	}
+	if at > endTime {
+		at = endTime
+	}
+	duration := big.NewInt(endTime - startTime)
+	elapsed := big.NewInt(at - startTime)
+	remaining := new(big.Int).Sub(duration, elapsed)
+
+	total := new(big.Int).Mul(start, remaining)
+	total.Add(total, new(big.Int).Mul(end, elapsed))
+	if total.Sign() == 0 {
+		return total
+	}
+	total.Sub(total, big.NewInt(1))
+	total.Quo(total, duration)
+	return total.Add(total, big.NewInt(1))
+}
@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"encoding/json"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestCurrentAmount(t *testing.T) {
+	start, end := big.NewInt(1000), big.NewInt(400)
+	require.Equal(t, "1000", currentAmount(start, end, 100, 200, 50).String())
+	require.Equal(t, "700", currentAmount(start, end, 100, 200, 150).String())
+	require.Equal(t, "400", currentAmount(start, end, 100, 200, 300).String())
+	// (1000 + 401*2) / 3 rounds up
+	require.Equal(t, "601", currentAmount(start, big.NewInt(401), 0, 3, 2).String())
+}
+
+func TestDutchListing_FeesNeverUnderpaid(t *testing.T) {
+	var collection CollectionResp
+	require.Nil(t, json.Unmarshal([]byte(`{"fees":[{"fee":2.5,"recipient":"0x0000a26b00c1F0DF003000390027140000fAa719","required":true},{"fee":7.5,"recipient":"0x1111111111111111111111111111111111111111","required":true}]}`), &collection))
+
+	offerer := "0x9d1E5C9bA1c7B8fB8a4fA3e6d1DA6e8a25E4b4A2"
+	token := &paymentTokenResp{Address: zeroAddress().Hex(), Decimals: 18}
+	start := decimal.RequireFromString("1.000000000000000333").Shift(18)
+	end := decimal.RequireFromString("0.123456789012345677").Shift(18)
+	p := &OrderParameters{
+		Offerer:       offerer,
+		StartTime:     1700000000,
+		EndTime:       1700000000 + 3607,
+		Consideration: collection.listingConsiderations(start, end, 1, token, offerer),
+	}
+
+	for _, item := range p.Consideration {
+		require.Equal(t, 1, item.StartAmount.Cmp(item.EndAmount))
+	}
+	for at := p.StartTime; at <= p.EndTime; at += 13 {
+		price := new(big.Int)
+		for _, item := range p.Consideration {
+			price.Add(price, currentAmount(item.StartAmount, item.EndAmount, p.StartTime, p.EndTime, at))
+		}
+		for i, fee := range collection.Fees {
+			share := decimal.NewFromBigInt(price, 0).Mul(decimal.NewFromFloat(fee.Fee)).Div(decimal.NewFromInt(100))
+			paid := currentAmount(p.Consideration[i+1].StartAmount, p.Consideration[i+1].EndAmount, p.StartTime, p.EndTime, at)
+			require.False(t, decimal.NewFromBigInt(paid, 0).LessThan(share.Floor()), "fee %d underpaid at %d", i, at)
+		}
+	}
+
+	curve := p.PriceCurve(18, 4)
+	require.Len(t, curve, 5)
+	require.Equal(t, "1.000000000000000333", curve[0].Price.String())
+	require.Equal(t, "0.123456789012345677", curve[4].Price.String())
+	require.True(t, curve[1].Price.LessThan(curve[0].Price))
+	require.Equal(t, "0.9", curve[0].Proceeds.Round(1).String())
+}
@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"strings"
+	"sync"
+)
+
+var ErrUnknownWallet = errors.New("wallet not managed")
+
+var ErrNotHeld = errors.New("nft not held by any wallet")
+
+// AccountManager trades one contract from several wallets. Every Account keeps
+// its own signer, Seaport counter and nonces; the manager routes each action
+// to the wallet it concerns.
+type AccountManager struct {
+	accounts []*Account
+
+	mu      sync.Mutex
+	nextBuy int
+}
+
+// Holding is an NFT together with the wallet holding it.
+type Holding struct {
+	Account *Account
+	NFT     *NFT
+}
+
+// NewAccountManager manages accounts, which must trade the same contract on
+// the same chain from distinct wallets.
+func NewAccountManager(accounts ...*Account) (*AccountManager, error) {
+	if len(accounts) == 0 {
+		return nil, errors.New("no account")
+	}
+	seen := make(map[common.Address]bool, len(accounts))
+	for _, account := range accounts {
+		if !strings.EqualFold(account.contract.Address, accounts[0].contract.Address) || account.chain.Name != accounts[0].chain.Name {
+			return nil, fmt.Errorf("account %s trades %s on %s, not %s on %s", account.WalletAddress().Hex(),
+				account.contract.Address, account.chain.Name, accounts[0].contract.Address, accounts[0].chain.Name)
+		}
+		if seen[account.WalletAddress()] {
+			return nil, fmt.Errorf("wallet %s added twice", account.WalletAddress().Hex())
+		}
+		seen[account.WalletAddress()] = true
+	}
+	return &AccountManager{accounts: accounts}, nil
+}
+
+// Accounts returns the managed accounts in the order they were added.
+func (m *AccountManager) Accounts() []*Account {
+	return m.accounts
+}
+
+// Account returns the account of wallet, nil when it is not managed.
+func (m *AccountManager) Account(wallet common.Address) *Account {
+	for _, account := range m.accounts {
+		if account.WalletAddress() == wallet {
+			return account
+		}
+	}
+	return nil
+}
+
+// GetNFTs lists the NFTs of the contract held by every wallet. An ERC-1155
+// token held by several wallets appears once per wallet.
+func (m *AccountManager) GetNFTs(ctx context.Context) ([]Holding, error) {
+	holdings := make([]Holding, 0)
+	for _, account := range m.accounts {
+		nfts, err := account.GetNFTs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", account.WalletAddress().Hex(), err)
+		}
+		for i := range nfts.Nfts {
+			holdings = append(holdings, Holding{Account: account, NFT: &nfts.Nfts[i]})
+		}
+	}
+	return holdings, nil
+}
+
+// Holder returns the wallet holding the most units of identifier, the one a
+// sale of the NFT has to be signed by.
+func (m *AccountManager) Holder(ctx context.Context, identifier string) (*Holding, error) {
+	holdings, err := m.GetNFTs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var holder *Holding
+	for i := range holdings {
+		h := &holdings[i]
+		if h.NFT.Identifier != identifier {
+			continue
+		}
+		if holder == nil || bigOrZero(h.NFT.Balance).Cmp(bigOrZero(holder.NFT.Balance)) > 0 {
+			holder = h
+		}
+	}
+	if holder == nil {
+		return nil, fmt.Errorf("%w: #%s", ErrNotHeld, identifier)
+	}
+	return holder, nil
+}
+
+// Buyer returns the account the next purchase or offer goes through, taking
+// the wallets in turn so that spending is spread across them.
+func (m *AccountManager) Buyer() *Account {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	account := m.accounts[m.nextBuy%len(m.accounts)]
+	m.nextBuy++
+	return account
+}
+
+// Transfer moves amount units of nft between two managed wallets.
+func (m *AccountManager) Transfer(ctx context.Context, nft *NFT, from, to common.Address, amount int64) (*ethtypes.Receipt, error) {
+	if from == to {
+		return nil, errors.New("transfer to the same wallet")
+	}
+	sender := m.Account(from)
+	if sender == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownWallet, from.Hex())
+	}
+	if m.Account(to) == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownWallet, to.Hex())
+	}
+	return sender.TransferNFT(ctx, nft, to, amount)
+}
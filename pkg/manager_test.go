@@ -0,0 +1,170 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	wallet "github.com/ethersphere/bee/pkg/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testContract = "0x300b105942d6d181cdfe8199fd48eb09d26efd24"
+
+// sendingBackend accepts transactions and mines them at once.
+type sendingBackend struct {
+	Backend
+	sent []*ethtypes.Transaction
+}
+
+func (b *sendingBackend) PendingCodeAt(context.Context, common.Address) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (b *sendingBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return uint64(len(b.sent)), nil
+}
+
+func (b *sendingBackend) HeaderByNumber(context.Context, *big.Int) (*ethtypes.Header, error) {
+	return &ethtypes.Header{Number: big.NewInt(1), BaseFee: big.NewInt(1)}, nil
+}
+
+func (b *sendingBackend) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (b *sendingBackend) PendingCallContract(context.Context, ethereum.CallMsg) ([]byte, error) {
+	return nil, nil
+}
+
+func (b *sendingBackend) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	return 100000, nil
+}
+
+func (b *sendingBackend) SendTransaction(_ context.Context, tx *ethtypes.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func (b *sendingBackend) NonceAt(context.Context, common.Address, *big.Int) (uint64, error) {
+	return uint64(len(b.sent)), nil
+}
+
+func (b *sendingBackend) BlockNumber(context.Context) (uint64, error) {
+	return 1, nil
+}
+
+func (b *sendingBackend) TransactionReceipt(_ context.Context, txHash common.Hash) (*ethtypes.Receipt, error) {
+	return &ethtypes.Receipt{TxHash: txHash, Status: ethtypes.ReceiptStatusSuccessful, BlockNumber: big.NewInt(1)}, nil
+}
+
+func testAccount(t *testing.T, backend Backend, client *http.Client) *Account {
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	chain, err := LookupChain("sepolia")
+	require.Nil(t, err)
+	account := &Account{
+		signer:   wallet.NewDefaultSigner(key),
+		contract: &contractInfo{Address: testContract, Chain: "sepolia", Collection: "test", ContractStandard: NftType721},
+		chain:    chain,
+		chainID:  big.NewInt(chain.ChainID),
+		client:   backend,
+	}
+	if client != nil {
+		var err error
+		account.http, err = httpAgent(client)
+		require.Nil(t, err)
+	}
+	return account
+}
+
+func TestAccountManager(t *testing.T) {
+	first, second := testAccount(t, nil, nil), testAccount(t, nil, nil)
+	manager, err := NewAccountManager(first, second)
+	require.Nil(t, err)
+	require.Equal(t, second, manager.Account(second.WalletAddress()))
+	require.Nil(t, manager.Account(common.HexToAddress(testContract)))
+
+	require.Equal(t, first, manager.Buyer())
+	require.Equal(t, second, manager.Buyer())
+	require.Equal(t, first, manager.Buyer())
+
+	_, err = NewAccountManager(first, first)
+	require.NotNil(t, err)
+	other := testAccount(t, nil, nil)
+	other.contract = &contractInfo{Address: "0x0000000000000000000000000000000000000001", Chain: "sepolia"}
+	_, err = NewAccountManager(first, other)
+	require.NotNil(t, err)
+
+	nft := &NFT{Identifier: "1", Contract: testContract, TokenStandard: NftType721}
+	_, err = manager.Transfer(context.Background(), nft, first.WalletAddress(), common.HexToAddress(testContract), 1)
+	require.True(t, errors.Is(err, ErrUnknownWallet))
+}
+
+func TestAccountManager_Holdings(t *testing.T) {
+	held := map[string]string{}
+	client := apiClient(func(w http.ResponseWriter, r *http.Request) {
+		for wallet, nfts := range held {
+			if strings.Contains(r.URL.Path, wallet) {
+				_, _ = w.Write([]byte(nfts))
+				return
+			}
+		}
+		_, _ = w.Write([]byte(`{"nfts": []}`))
+	})
+	first, second := testAccount(t, nil, client), testAccount(t, nil, client)
+	held[first.WalletAddress().Hex()] = `{"nfts": [{"identifier": "1", "contract": "` + testContract + `", "token_standard": "erc721"}]}`
+	held[second.WalletAddress().Hex()] = `{"nfts": [{"identifier": "2", "contract": "` + testContract + `", "token_standard": "erc721"},
+		{"identifier": "3", "contract": "` + testContract + `", "token_standard": "erc721"}]}`
+	manager, err := NewAccountManager(first, second)
+	require.Nil(t, err)
+
+	holdings, err := manager.GetNFTs(context.Background())
+	require.Nil(t, err)
+	require.Len(t, holdings, 3)
+
+	holder, err := manager.Holder(context.Background(), "3")
+	require.Nil(t, err)
+	require.Equal(t, second, holder.Account)
+
+	_, err = manager.Holder(context.Background(), "4")
+	require.True(t, errors.Is(err, ErrNotHeld))
+}
+
+func TestTransferNFT(t *testing.T) {
+	backend := &sendingBackend{}
+	from, to := testAccount(t, backend, nil), testAccount(t, nil, nil)
+	manager, err := NewAccountManager(from, to)
+	require.Nil(t, err)
+	ctx := context.Background()
+
+	_, err = manager.Transfer(ctx, &NFT{Identifier: "7", Contract: testContract, TokenStandard: NftType721}, from.WalletAddress(), to.WalletAddress(), 1)
+	require.Nil(t, err)
+	_, err = manager.Transfer(ctx, &NFT{Identifier: "8", Contract: testContract, TokenStandard: NftType1155}, from.WalletAddress(), to.WalletAddress(), 5)
+	require.Nil(t, err)
+	require.Len(t, backend.sent, 2)
+
+	// safeTransferFrom(address,address,uint256)
+	data := backend.sent[0].Data()
+	require.Equal(t, "42842e0e", common.Bytes2Hex(data[:4]))
+	require.Equal(t, from.WalletAddress(), common.BytesToAddress(data[4:36]))
+	require.Equal(t, to.WalletAddress(), common.BytesToAddress(data[36:68]))
+	require.Equal(t, int64(7), new(big.Int).SetBytes(data[68:100]).Int64())
+
+	// safeTransferFrom(address,address,uint256,uint256,bytes)
+	data = backend.sent[1].Data()
+	require.Equal(t, "f242432a", common.Bytes2Hex(data[:4]))
+	require.Equal(t, int64(5), new(big.Int).SetBytes(data[100:132]).Int64())
+	sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(from.chainID), backend.sent[1])
+	require.Nil(t, err)
+	require.Equal(t, from.WalletAddress(), sender)
+
+	_, err = from.TransferNFT(ctx, &NFT{Identifier: "8", Contract: testContract, TokenStandard: NftType1155}, to.WalletAddress(), 0)
+	require.NotNil(t, err)
+}
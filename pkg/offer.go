@@ -0,0 +1,125 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"log"
+	"math/big"
+	"time"
+)
+
+// CreateOffer bids price WETH on nft. The WETH is the offer item; the NFT and the
+// collection fees, which are taken out of the bid, are the consideration.
+func (a *Account) CreateOffer(ctx context.Context, nft *NFT, price string, expire int) (*CreateListingResp, error) {
+	startTime := time.Now().Local().Unix()
+	endTime := time.Now().Local().Add(time.Duration(expire) * time.Minute).Unix()
+
+	offer, offerPrice, err := a.wethOfferItem(ctx, price, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := a.GetCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counter, err := a.seaportInstance.GetCounter(nil, a.WalletAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	identifierOrCriteria, err := nft.identifier()
+	if err != nil {
+		return nil, err
+	}
+	fees, _ := collection.feeConsiderations(offerPrice, offer.ItemType, offer.Token)
+	considerations := append([]ConsiderationItem{
+		{
+			ItemType:             nft.nftType(),
+			Token:                common.HexToAddress(nft.Contract).Hex(),
+			IdentifierOrCriteria: identifierOrCriteria,
+			StartAmount:          big.NewInt(1),
+			EndAmount:            big.NewInt(1),
+			Recipient:            a.WalletAddress().Hex(),
+		},
+	}, fees...)
+
+	param := OrderParameters{
+		Offerer:                         a.WalletAddress().Hex(),
+		Zone:                            zeroAddress().Hex(),
+		ZoneHash:                        zero32BytesHexString(),
+		StartTime:                       startTime,
+		EndTime:                         endTime,
+		OrderType:                       0, // FULL_OPEN
+		Salt:                            fixedSalt(),
+		ConduitKey:                      a.chain.ConduitKey,
+		Offer:                           []OfferItem{offer},
+		Consideration:                   considerations,
+		TotalOriginalConsiderationItems: len(considerations),
+		Counter:                         counter,
+	}
+
+	if err := a.checkBidExposure(ctx, param.Offer, false); err != nil {
+		return nil, err
+	}
+	if err := a.ensureApprovals(ctx, param.Offer); err != nil {
+		return nil, err
+	}
+	if err := a.checkBidExposure(ctx, param.Offer, true); err != nil {
+		return nil, err
+	}
+	data, err := param.signTypedData(a)
+	if err != nil {
+		return nil, err
+	}
+	orderHash, err := a.trackOrder(SideOffer, data, price)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := a.postOrder(ctx, "offers", orderHash, data)
+	if err != nil {
+		return nil, err
+	}
+	a.markOrder(orderHash, OrderActive)
+
+	log.Println(output)
+	return output, nil
+}
+
+// wethOfferItem returns the WETH offer item paying price for each of quantity
+// items, along with the total amount in the token's smallest unit.
+func (a *Account) wethOfferItem(ctx context.Context, price string, quantity int) (OfferItem, decimal.Decimal, error) {
+	weth := a.chain.WrappedToken
+	if weth == "" {
+		return OfferItem{}, decimal.Zero, fmt.Errorf("no wrapped native token known for chain %s", a.chain.Name)
+	}
+	paymentToken, err := a.paymentToken(ctx, weth)
+	if err != nil {
+		return OfferItem{}, decimal.Zero, err
+	}
+
+	offerPrice, err := decimal.NewFromString(price)
+	if err != nil {
+		return OfferItem{}, decimal.Zero, err
+	}
+	if offerPrice.IsZero() {
+		return OfferItem{}, decimal.Zero, errors.New("price is zero")
+	}
+	if quantity <= 0 {
+		return OfferItem{}, decimal.Zero, errors.New("quantity must be positive")
+	}
+	amount := offerPrice.Shift(int32(paymentToken.Decimals)).Mul(decimal.NewFromInt(int64(quantity)))
+
+	return OfferItem{
+		ItemType:             1, // ERC20
+		Token:                common.HexToAddress(weth).Hex(),
+		IdentifierOrCriteria: new(big.Int),
+		StartAmount:          amount.BigInt(),
+		EndAmount:            amount.BigInt(),
+	}, amount, nil
+}
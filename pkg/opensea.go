@@ -9,18 +9,13 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
-	wallet "github.com/ethersphere/bee/pkg/crypto"
 	"github.com/ethersphere/bee/pkg/crypto/eip712"
 	"github.com/parnurzeal/gorequest"
 	"github.com/shopspring/decimal"
 	"log"
 	"math/big"
-	"opensea-bot/pkg/seaport"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -29,6 +24,16 @@ const SeaportConduitKey = "0x0000007b02230091a7ed01230072f7006a004d60a8d4e71d599
 
 const ProtocolAddress = "0x00000000000000adc04c56bf30ac9d3c0aaf14dc"
 
+// SeaportV16Address is the Seaport 1.6 deployment, at the same address on
+// every chain like ProtocolAddress (Seaport 1.5).
+const SeaportV16Address = "0x0000000000000068f116a894984e2db1123eb395"
+
+// seaportVersions are the Seaport deployments orders are fulfilled on.
+var seaportVersions = map[common.Address]string{
+	common.HexToAddress(ProtocolAddress):   "1.5",
+	common.HexToAddress(SeaportV16Address): "1.6",
+}
+
 var request = gorequest.New()
 
 func init() {
@@ -36,54 +41,13 @@ func init() {
 	request.Header.Add("x-api-key", os.Getenv("OPENSEA_API_KEY"))
 }
 
-func NewAccount(ctx context.Context, contractAddress, chain string) *Account {
-	privateKey, err := crypto.HexToECDSA(os.Getenv("PRIVATE_KEY"))
-	if err != nil {
-		log.Fatal(err)
-	}
-	signer := wallet.NewDefaultSigner(privateKey)
-	walletAddress, err := signer.EthereumAddress()
-	if err != nil {
-		log.Fatal("error casting public key to ECDSA", err)
-	}
-
-	var info *contractInfo
-	req := request.Clone().Get(fmt.Sprintf("%s/api/v2/chain/%s/contract/%s", getOpenSeaAPI(chain), chain, contractAddress))
-
-	log.Println(req.AsCurlCommand())
-	resp, _, errs := req.EndStruct(&info)
-	if len(errs) > 0 {
-		panic(errs[0])
-	}
-	log.Println(resp)
-	log.Println("wallet address: ", walletAddress.Hex())
-
-	cli, err := ethclient.Dial(getRpcURL(chain))
-	if err != nil {
-		panic(err)
-	}
-
-	chainID, err := cli.ChainID(ctx)
-	if err != nil {
-		panic(err)
-	}
-
-	seaportInstance, err := seaport.NewSeaport(common.HexToAddress(ProtocolAddress), cli)
-	if err != nil {
-		panic(err)
-	}
-
-	return &Account{
-		signer:          signer,
-		contract:        info,
-		seaportInstance: seaportInstance,
-		chainID:         chainID,
-	}
+func (a *Account) GetCollection(ctx context.Context) (*CollectionResp, error) {
+	return a.getCollection(ctx, a.contract.Collection)
 }
 
-func (a *Account) GetCollection(ctx context.Context) (*CollectionResp, error) {
+func (a *Account) getCollection(ctx context.Context, slug string) (*CollectionResp, error) {
 	var data *CollectionResp
-	req := request.Clone().Get(fmt.Sprintf("%s/api/v2/collections/%s", getOpenSeaAPI(a.contract.Chain), a.contract.Collection))
+	req := a.newRequest().Get(fmt.Sprintf("%s/api/v2/collections/%s", getOpenSeaAPI(a.contract.Chain), slug))
 	log.Println(req.AsCurlCommand())
 	resp, _, errs := req.EndStruct(&data)
 	if len(errs) > 0 {
@@ -93,9 +57,11 @@ func (a *Account) GetCollection(ctx context.Context) (*CollectionResp, error) {
 	return data, nil
 }
 
+// GetNFTs returns the NFTs of the collection held by the wallet, with their
+// balance read on-chain.
 func (a *Account) GetNFTs(ctx context.Context) (*AccountNFTsResp, error) {
 	var data *AccountNFTsResp
-	req := request.Clone().
+	req := a.newRequest().
 		Get(fmt.Sprintf("%s/api/v2/chain/%s/account/%s/nfts", getOpenSeaAPI(a.contract.Chain), a.contract.Chain, a.WalletAddress().Hex())).
 		Param("collection", a.contract.Collection)
 	log.Println(req.AsCurlCommand())
@@ -104,12 +70,15 @@ func (a *Account) GetNFTs(ctx context.Context) (*AccountNFTsResp, error) {
 		return nil, errs[0]
 	}
 	log.Println(resp)
+	if err := a.fillBalances(ctx, data.Nfts); err != nil {
+		return nil, err
+	}
 	return data, nil
 }
 
 func (a *Account) GetBestListingByNFT(ctx context.Context, identifier string) (*BestListingResp, error) {
 	var data *BestListingResp
-	req := request.Clone().
+	req := a.newRequest().
 		Get(fmt.Sprintf("%s/api/v2/listings/collection/%s/nfts/%s/best", getOpenSeaAPI(a.contract.Chain), a.contract.Collection, identifier))
 	log.Println(req.AsCurlCommand())
 	resp, _, errs := req.EndStruct(&data)
@@ -122,7 +91,7 @@ func (a *Account) GetBestListingByNFT(ctx context.Context, identifier string) (*
 
 func (a *Account) GetBestListing(ctx context.Context, limit int) ([]BestListingResp, error) {
 	var data *BestListingListResp
-	req := request.Clone().
+	req := a.newRequest().
 		Get(fmt.Sprintf("%s/api/v2/listings/collection/%s/best?limit=%d", getOpenSeaAPI(a.contract.Chain), a.contract.Collection, limit))
 	log.Println(req.AsCurlCommand())
 	resp, _, errs := req.EndStruct(&data)
@@ -133,109 +102,223 @@ func (a *Account) GetBestListing(ctx context.Context, limit int) ([]BestListingR
 	return data.Listings, nil
 }
 
-func (a *Account) CreateListing(ctx context.Context, nft *NFT, price string, expire int) error {
+// CreateListing lists a single unit of nft at price.
+func (a *Account) CreateListing(ctx context.Context, nft *NFT, price string, expire int) (*CreateListingResp, error) {
+	return a.CreateQuantityListing(ctx, nft, 1, price, expire)
+}
+
+// CreateQuantityListing lists quantity units of an ERC-1155 nft at unitPrice
+// each. ERC-1155 listings are partially fillable so buyers may take any number
+// of units.
+func (a *Account) CreateQuantityListing(ctx context.Context, nft *NFT, quantity int64, unitPrice string, expire int) (*CreateListingResp, error) {
+	return a.createListing(ctx, nft, quantity, unitPrice, unitPrice, expire, zeroAddress())
+}
+
+// createListing lists quantity units of nft for expire minutes. The unit price
+// declines linearly from startPrice to endPrice over that time, it is fixed when
+// both are equal. Only taker may buy the listing unless it is the zero address.
+func (a *Account) createListing(ctx context.Context, nft *NFT, quantity int64, startPrice, endPrice string, expire int, taker common.Address) (*CreateListingResp, error) {
 	startTime := big.NewInt(time.Now().Local().Unix())
 	endTime := big.NewInt(time.Now().Local().Add(time.Duration(expire) * time.Minute).Unix())
 
-	paymentToken, err := a.contract.paymentToken(ctx)
+	if quantity <= 0 {
+		return nil, errors.New("quantity must be positive")
+	}
+	if quantity > 1 && nft.nftType() != 3 {
+		return nil, fmt.Errorf("%s #%s is not an ERC-1155 token, only one unit can be listed", nft.Contract, nft.Identifier)
+	}
+	if nft.Balance != nil && nft.Balance.Cmp(big.NewInt(quantity)) < 0 {
+		return nil, fmt.Errorf("cannot list %d units of %s #%s, balance is %s", quantity, nft.Contract, nft.Identifier, nft.Balance)
+	}
+
+	paymentToken, err := a.paymentToken(ctx, a.paymentTokenAddress)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	listPrice, err := decimal.NewFromString(price)
+	startUnit, err := decimal.NewFromString(startPrice)
+	if err != nil {
+		return nil, err
+	}
+	endUnit, err := decimal.NewFromString(endPrice)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if listPrice.IsZero() {
-		return errors.New("price is zero")
+	if startUnit.IsZero() || endUnit.IsZero() {
+		return nil, errors.New("price is zero")
 	}
-	listPrice = listPrice.Shift(int32(paymentToken.Decimals))
+	if endUnit.GreaterThan(startUnit) {
+		return nil, errors.New("end price is above start price")
+	}
+	startUnit = startUnit.Shift(int32(paymentToken.Decimals))
+	endUnit = endUnit.Shift(int32(paymentToken.Decimals))
 
 	collection, err := a.GetCollection(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	identifierOrCriteria, _ := strconv.Atoi(nft.Identifier)
+	identifierOrCriteria, err := nft.identifier()
+	if err != nil {
+		return nil, err
+	}
 	offer := OfferItem{
 		ItemType:             nft.nftType(),
 		Token:                common.HexToAddress(nft.Contract).Hex(),
-		StartAmount:          1,
-		EndAmount:            1,
-		IdentifierOrCriteria: int64(identifierOrCriteria),
+		StartAmount:          big.NewInt(quantity),
+		EndAmount:            big.NewInt(quantity),
+		IdentifierOrCriteria: identifierOrCriteria,
 	}
 
 	counter, err := a.seaportInstance.GetCounter(nil, a.WalletAddress())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	considerations := make([]ConsiderationItem, 0)
-	var totalFee = decimal.Zero
-	for _, fee := range collection.Fees {
-		if fee.Required {
-			feeAmount := listPrice.Mul(decimal.NewFromFloat(fee.Fee)).Div(decimal.NewFromInt(100))
-			totalFee = totalFee.Add(feeAmount)
-			considerations = append(considerations, ConsiderationItem{
-				ItemType:             0,
-				Token:                paymentToken.Address,
-				IdentifierOrCriteria: 0,
-				StartAmount:          feeAmount.BigInt().Int64(),
-				EndAmount:            feeAmount.BigInt().Int64(),
-				Recipient:            fee.Recipient,
-			})
-		}
+	considerations := collection.listingConsiderations(startUnit, endUnit, quantity, paymentToken, a.WalletAddress().Hex())
+	orderType := uint8(0) // FULL_OPEN
+	if taker != zeroAddress() {
+		considerations = append(considerations, privateSaleItem(offer, taker))
+	} else if nft.nftType() == 3 {
+		orderType = 1 // PARTIAL_OPEN
 	}
-	considerations = append([]ConsiderationItem{
-		{
-			ItemType:             0,
-			Token:                paymentToken.Address,
-			IdentifierOrCriteria: 0,
-			StartAmount:          listPrice.Sub(totalFee).BigInt().Int64(),
-			EndAmount:            listPrice.Sub(totalFee).BigInt().Int64(),
-			Recipient:            a.WalletAddress().Hex(),
-		},
-	}, considerations...)
 	param := OrderParameters{
 		Offerer:                         a.WalletAddress().Hex(),
 		Zone:                            zeroAddress().Hex(),
 		ZoneHash:                        zero32BytesHexString(),
 		StartTime:                       startTime.Int64(),
 		EndTime:                         endTime.Int64(),
-		OrderType:                       0, // FULL_OPEN
+		OrderType:                       orderType,
 		Salt:                            fixedSalt(),
-		ConduitKey:                      SeaportConduitKey,
+		ConduitKey:                      a.chain.ConduitKey,
 		Offer:                           []OfferItem{offer},
 		Consideration:                   considerations,
 		TotalOriginalConsiderationItems: len(considerations),
-		Counter:                         counter.Int64(),
+		Counter:                         counter,
 	}
 
+	price := startPrice
+	if !startUnit.Equal(endUnit) {
+		price = startPrice + "->" + endPrice
+		for _, point := range param.PriceCurve(paymentToken.Decimals, 10) {
+			log.Printf("dutch auction %s #%s at %s: %s, proceeds %s", nft.Contract, nft.Identifier,
+				point.At.Format(time.RFC3339), point.Price, point.Proceeds)
+		}
+	}
+
+	if err := a.ensureApprovals(ctx, param.Offer); err != nil {
+		return nil, err
+	}
 	data, err := param.signTypedData(a)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	orderHash, err := a.trackOrder(SideListing, data, price)
+	if err != nil {
+		return nil, err
 	}
 
-	req := request.Clone().
-		Post(fmt.Sprintf("%s/api/v2/orders/%s/seaport/listings", getOpenSeaAPI(a.contract.Chain), a.contract.Chain)).Send(data)
+	output, err := a.postOrder(ctx, "listings", orderHash, data)
+	if err != nil {
+		return nil, err
+	}
+	a.markOrder(orderHash, OrderActive)
+
+	log.Println(output)
+	return output, nil
+}
+
+// ErrOrderRejected is returned when OpenSea refuses a posted order.
+var ErrOrderRejected = errors.New("order rejected by opensea")
+
+// postOrder submits a signed order to OpenSea and checks the order hash it
+// reports against orderHash.
+func (a *Account) postOrder(ctx context.Context, side, orderHash string, data *protocolData) (*CreateListingResp, error) {
+	req := a.newRequest().
+		Post(fmt.Sprintf("%s/api/v2/orders/%s/seaport/%s", getOpenSeaAPI(a.contract.Chain), a.contract.Chain, side)).Send(data)
 
 	log.Println(req.AsCurlCommand())
 
 	var output *CreateListingResp
-	_, _, errs := req.EndStruct(&output)
+	resp, body, errs := req.EndStruct(&output)
+	if resp != nil && resp.StatusCode >= 300 {
+		a.markOrder(orderHash, OrderRejected)
+		return nil, fmt.Errorf("%w: %s: %s", ErrOrderRejected, resp.Status, body)
+	}
 	if len(errs) > 0 {
-		return errs[0]
+		return nil, errs[0]
+	}
+	if err := checkOrderHash(orderHash, output.Order.OrderHash); err != nil {
+		return nil, err
 	}
+	return output, nil
+}
 
-	log.Println(output)
-	return nil
+// listingConsiderations returns the seller proceeds followed by the required
+// fees for quantity units priced from startUnit to endUnit, in the token's
+// smallest unit. Fees are split on both prices the same way, rounded up, and
+// the seller takes the remainder. Amounts are computed per unit then scaled, so
+// every item divides evenly by quantity and a partial fill of any number of
+// units pays exact amounts.
+func (c *CollectionResp) listingConsiderations(startUnit, endUnit decimal.Decimal, quantity int64, paymentToken *paymentTokenResp, recipient string) []ConsiderationItem {
+	itemType := paymentToken.itemType()
+	fees, startFee := c.feeConsiderations(startUnit, itemType, paymentToken.Address)
+	endFees, endFee := c.feeConsiderations(endUnit, itemType, paymentToken.Address)
+	for i := range fees {
+		fees[i].EndAmount = endFees[i].EndAmount
+	}
+	considerations := append([]ConsiderationItem{
+		{
+			ItemType:             itemType,
+			Token:                paymentToken.Address,
+			IdentifierOrCriteria: new(big.Int),
+			StartAmount:          startUnit.Sub(startFee).BigInt(),
+			EndAmount:            endUnit.Sub(endFee).BigInt(),
+			Recipient:            recipient,
+		},
+	}, fees...)
+
+	units := big.NewInt(quantity)
+	for i := range considerations {
+		considerations[i].StartAmount = new(big.Int).Mul(considerations[i].StartAmount, units)
+		considerations[i].EndAmount = new(big.Int).Mul(considerations[i].EndAmount, units)
+	}
+	return considerations
+}
+
+// feeConsiderations returns one consideration item per required collection fee
+// on amount, paid in token, together with the total fee. Fees are rounded up to
+// the token's smallest unit so a recipient is never paid less than its share.
+func (c *CollectionResp) feeConsiderations(amount decimal.Decimal, itemType uint8, token string) ([]ConsiderationItem, decimal.Decimal) {
+	considerations := make([]ConsiderationItem, 0)
+	var totalFee = decimal.Zero
+	for _, fee := range c.Fees {
+		if fee.Required {
+			feeAmount := amount.Mul(decimal.NewFromFloat(fee.Fee)).Div(decimal.NewFromInt(100)).Ceil()
+			totalFee = totalFee.Add(feeAmount)
+			considerations = append(considerations, ConsiderationItem{
+				ItemType:             itemType,
+				Token:                token,
+				IdentifierOrCriteria: new(big.Int),
+				StartAmount:          feeAmount.BigInt(),
+				EndAmount:            feeAmount.BigInt(),
+				Recipient:            fee.Recipient,
+			})
+		}
+	}
+	return considerations, totalFee
 }
 
-func (c *contractInfo) paymentToken(ctx context.Context) (*paymentTokenResp, error) {
+// paymentToken describes the token at address, the chain's default listing
+// currency when address is empty.
+func (a *Account) paymentToken(ctx context.Context, address string) (*paymentTokenResp, error) {
+	if address == "" {
+		address = a.chain.PaymentToken
+	}
 	var data *paymentTokenResp
-	req := request.Clone().
-		Get(fmt.Sprintf("%s/api/v2/chain/%s/payment_token/0x0000000000000000000000000000000000000000", getOpenSeaAPI(c.Chain), c.Chain))
+	req := a.newRequest().
+		Get(fmt.Sprintf("%s/api/v2/chain/%s/payment_token/%s", getOpenSeaAPI(a.contract.Chain), a.contract.Chain, address))
 	log.Println(req.AsCurlCommand())
 	resp, _, errs := req.EndStruct(&data)
 	if len(errs) > 0 {
@@ -249,6 +332,19 @@ func (c *contractInfo) Stats() {
 
 }
 
+// NFT describes the token identifier of the account's contract.
+func (a *Account) NFT(identifier string) *NFT {
+	return &NFT{
+		Identifier:    identifier,
+		Contract:      a.contract.Address,
+		TokenStandard: a.contract.ContractStandard,
+	}
+}
+
+func (a *Account) SetPaymentToken(address string) {
+	a.paymentTokenAddress = address
+}
+
 func (a *Account) WalletAddress() common.Address {
 	address, _ := a.signer.EthereumAddress()
 	return address
@@ -263,48 +359,11 @@ func (p *OrderParameters) signTypedData(account *Account) (*protocolData, error)
 			Name:              name,
 			Version:           info.Version,
 			ChainId:           math.NewHexOrDecimal256(account.chainID.Int64()),
-			VerifyingContract: ProtocolAddress,
+			VerifyingContract: account.chain.SeaportAddress,
 		},
-		Message: map[string]interface{}{},
 	}
 	_ = json.Unmarshal([]byte(types), &data.Types)
-	salt, _ := big.NewInt(0).SetString(p.Salt, 0)
-
-	offer := make([]interface{}, 0)
-	for _, item := range p.Offer {
-		offer = append(offer, map[string]interface{}{
-			"itemType":             big.NewInt(int64(item.ItemType)),
-			"token":                item.Token,
-			"identifierOrCriteria": big.NewInt(item.IdentifierOrCriteria),
-			"startAmount":          big.NewInt(item.StartAmount),
-			"endAmount":            big.NewInt(item.EndAmount),
-		})
-	}
-
-	data.Message["offer"] = offer
-
-	consideration := make([]interface{}, 0)
-	for _, item := range p.Consideration {
-		consideration = append(consideration, map[string]interface{}{
-			"itemType":             big.NewInt(int64(item.ItemType)),
-			"token":                item.Token,
-			"identifierOrCriteria": big.NewInt(item.IdentifierOrCriteria),
-			"startAmount":          big.NewInt(item.StartAmount),
-			"endAmount":            big.NewInt(item.EndAmount),
-			"recipient":            item.Recipient,
-		})
-	}
-
-	data.Message["consideration"] = consideration
-	data.Message["offerer"] = p.Offerer
-	data.Message["startTime"] = big.NewInt(p.StartTime)
-	data.Message["endTime"] = big.NewInt(p.EndTime)
-	data.Message["orderType"] = big.NewInt(int64(p.OrderType))
-	data.Message["zone"] = p.Zone
-	data.Message["zoneHash"] = hexStringToByte32(p.ZoneHash)
-	data.Message["salt"] = salt
-	data.Message["conduitKey"] = hexStringToByte32(p.ConduitKey)
-	data.Message["counter"] = big.NewInt(p.Counter)
+	data.Message = p.message()
 
 	str, _ := json.Marshal(data)
 
@@ -318,15 +377,15 @@ func (p *OrderParameters) signTypedData(account *Account) (*protocolData, error)
 	return &protocolData{
 		Parameters:      *p,
 		Signature:       hexutil.Encode(sign),
-		ProtocolAddress: ProtocolAddress,
+		ProtocolAddress: account.chain.SeaportAddress,
 	}, nil
 }
 
-func (c *contractInfo) lastSaleCost(nft *NFT) (*payment, error) {
+func (a *Account) lastSaleCost(nft *NFT) (*payment, error) {
 	var data *SaleResp
-	req := request.Clone().Get(
+	req := a.newRequest().Get(
 		fmt.Sprintf("%s/api/v2/events/chain/%s/contract/%s/nfts/%s?event_type=sale&limit=1",
-			getOpenSeaAPI(c.Chain), c.Chain, nft.Contract, nft.Identifier))
+			getOpenSeaAPI(a.contract.Chain), a.contract.Chain, nft.Contract, nft.Identifier))
 	log.Println(req.AsCurlCommand())
 	resp, _, errs := req.EndStruct(&data)
 	if len(errs) > 0 {
@@ -3,14 +3,20 @@ package pkg
 import (
 	"context"
 	"github.com/stretchr/testify/require"
+	"os"
 	"testing"
 )
 
 func TestAccount_CreateListing(t *testing.T) {
-	account := NewAccount(context.TODO(), "0x300b105942d6d181cdfe8199fd48eb09d26efd24", "sepolia")
+	if os.Getenv("PRIVATE_KEY") == "" {
+		t.Skip("PRIVATE_KEY not set")
+	}
+	account, err := NewAccount(context.TODO(), WithContract("0x300b105942d6d181cdfe8199fd48eb09d26efd24"), WithChain("sepolia"))
+	require.Nil(t, err)
+	defer account.Close()
 
 	nfts, err := account.GetNFTs(context.TODO())
 	require.Nil(t, err)
 
-	t.Log(account.CreateListing(context.TODO(), &nfts.Nfts[0], "0.289"))
+	t.Log(account.CreateListing(context.TODO(), &nfts.Nfts[0], "0.289", 60))
 }
@@ -0,0 +1,217 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	wallet "github.com/ethersphere/bee/pkg/crypto"
+	"github.com/parnurzeal/gorequest"
+	"log"
+	"net/http"
+	"opensea-bot/pkg/seaport"
+	"os"
+)
+
+// Stages of NewAccount reported by AccountError.
+const (
+	StageOptions  = "options"
+	StageChain    = "chain"
+	StageSigner   = "signer"
+	StageBackend  = "backend"
+	StageContract = "contract"
+	StageSeaport  = "seaport"
+)
+
+// AccountError is returned by NewAccount, Stage tells which step failed.
+type AccountError struct {
+	Stage string
+	Err   error
+}
+
+func (e *AccountError) Error() string {
+	return fmt.Sprintf("new account: %s: %v", e.Stage, e.Err)
+}
+
+func (e *AccountError) Unwrap() error {
+	return e.Err
+}
+
+type accountOptions struct {
+	contract   string
+	chain      string
+	signer     Signer
+	httpClient *http.Client
+	backend    Backend
+	rpc        []string
+	txConfig   *TxConfig
+}
+
+type Option func(*accountOptions)
+
+// WithContract sets the NFT contract the account trades, it is required.
+func WithContract(address string) Option {
+	return func(o *accountOptions) { o.contract = address }
+}
+
+// WithChain sets the chain by registry name or alias, ethereum by default.
+func WithChain(name string) Option {
+	return func(o *accountOptions) { o.chain = name }
+}
+
+// WithSigner signs orders and transactions with signer instead of the key in
+// the PRIVATE_KEY environment variable.
+func WithSigner(signer Signer) Option {
+	return func(o *accountOptions) { o.signer = signer }
+}
+
+// WithHTTPClient sends the OpenSea API requests through client, whose
+// transport, when set, must be an *http.Transport.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *accountOptions) { o.httpClient = client }
+}
+
+// WithBackend talks to the chain through backend, an RPCPool, an ethclient or a
+// simulated backend. Transactions are signed for the chain id it reports.
+func WithBackend(backend Backend) Option {
+	return func(o *accountOptions) { o.backend = backend }
+}
+
+// WithRPC dials urls instead of the chain's registry endpoint. It is ignored
+// when a backend is set.
+func WithRPC(urls ...string) Option {
+	return func(o *accountOptions) { o.rpc = urls }
+}
+
+// WithTxConfig sets how the account's transactions are priced and replaced,
+// DefaultTxConfig otherwise.
+func WithTxConfig(config TxConfig) Option {
+	return func(o *accountOptions) { o.txConfig = &config }
+}
+
+// NewAccount sets up an account trading the contract given by WithContract.
+// Unless a backend is passed it dials the chain's RPC endpoints, which Close
+// releases.
+func NewAccount(ctx context.Context, opts ...Option) (*Account, error) {
+	o := &accountOptions{chain: "ethereum"}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if !common.IsHexAddress(o.contract) {
+		return nil, &AccountError{StageOptions, fmt.Errorf("invalid contract address %q", o.contract)}
+	}
+
+	network, err := LookupChain(o.chain)
+	if err != nil {
+		return nil, &AccountError{StageChain, err}
+	}
+
+	signer := o.signer
+	if signer == nil {
+		privateKey, err := crypto.HexToECDSA(os.Getenv("PRIVATE_KEY"))
+		if err != nil {
+			return nil, &AccountError{StageSigner, fmt.Errorf("PRIVATE_KEY: %w", err)}
+		}
+		signer = wallet.NewDefaultSigner(privateKey)
+	}
+	walletAddress, err := signer.EthereumAddress()
+	if err != nil {
+		return nil, &AccountError{StageSigner, err}
+	}
+	log.Println("wallet address: ", walletAddress.Hex())
+
+	account := &Account{
+		signer: signer,
+		chain:  network,
+		http:   request,
+		client: o.backend,
+	}
+	if o.httpClient != nil {
+		if account.http, err = httpAgent(o.httpClient); err != nil {
+			return nil, &AccountError{StageOptions, err}
+		}
+	}
+
+	if account.client == nil {
+		pool, err := DialChain(ctx, network, o.rpc...)
+		if err != nil {
+			return nil, &AccountError{StageBackend, err}
+		}
+		account.client = pool
+		account.closeBackend = pool.Close
+	}
+	if account.chainID, err = account.client.ChainID(ctx); err != nil {
+		account.Close()
+		return nil, &AccountError{StageBackend, err}
+	}
+
+	txConfig := DefaultTxConfig()
+	if o.txConfig != nil {
+		txConfig = *o.txConfig
+	}
+	if account.txs, err = NewTxManager(account.client, signer, account.chainID, txConfig); err != nil {
+		account.Close()
+		return nil, &AccountError{StageSigner, err}
+	}
+
+	if account.contract, err = account.fetchContractInfo(ctx, network.Name, o.contract); err != nil {
+		account.Close()
+		return nil, &AccountError{StageContract, err}
+	}
+
+	account.seaportInstance, err = seaport.NewSeaport(common.HexToAddress(network.SeaportAddress), account.client)
+	if err != nil {
+		account.Close()
+		return nil, &AccountError{StageSeaport, err}
+	}
+	return account, nil
+}
+
+// Close releases the RPC connections NewAccount dialed, a backend passed with
+// WithBackend is left open.
+func (a *Account) Close() {
+	if a.closeBackend != nil {
+		a.closeBackend()
+		a.closeBackend = nil
+	}
+}
+
+// httpAgent returns a request agent sending through a copy of client.
+// gorequest sets the agent's Transport on its client before every request, so
+// the client's transport becomes the agent's and must be an *http.Transport.
+func httpAgent(client *http.Client) (*gorequest.SuperAgent, error) {
+	agent := request.Clone()
+	copied := *client
+	agent.Client = &copied
+	if client.Transport != nil {
+		transport, ok := client.Transport.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("http client transport %T is not an *http.Transport", client.Transport)
+		}
+		agent.Transport = transport
+	}
+	return agent, nil
+}
+
+// newRequest returns a request agent using the account's HTTP client.
+func (a *Account) newRequest() *gorequest.SuperAgent {
+	if a.http == nil {
+		return request.Clone()
+	}
+	return a.http.Clone()
+}
+
+func (a *Account) fetchContractInfo(ctx context.Context, chain, contract string) (*contractInfo, error) {
+	var info *contractInfo
+	req := a.newRequest().Get(fmt.Sprintf("%s/api/v2/chain/%s/contract/%s", getOpenSeaAPI(chain), chain, contract))
+	log.Println(req.AsCurlCommand())
+	resp, _, errs := req.EndStruct(&info)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	log.Println(resp)
+	if info == nil || info.Collection == "" {
+		return nil, fmt.Errorf("no collection found for contract %s", contract)
+	}
+	return info, nil
+}
@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"github.com/ethereum/go-ethereum/crypto"
+	wallet "github.com/ethersphere/bee/pkg/crypto"
+	"github.com/parnurzeal/gorequest"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// chainBackend is a Backend that only knows its chain id.
+type chainBackend struct {
+	Backend
+	chainID int64
+}
+
+func (b *chainBackend) ChainID(context.Context) (*big.Int, error) {
+	return big.NewInt(b.chainID), nil
+}
+
+// apiClient routes every request to handler instead of OpenSea.
+func apiClient(handler http.HandlerFunc) *http.Client {
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		handler(rec, r)
+		return rec.Result(), nil
+	})
+	// an empty TLSNextProto keeps HTTP/2 from claiming https
+	transport := &http.Transport{TLSNextProto: map[string]func(string, *tls.Conn) http.RoundTripper{}}
+	transport.RegisterProtocol("https", rt)
+	transport.RegisterProtocol("http", rt)
+	return &http.Client{Transport: transport}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestNewAccount(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	signer := wallet.NewDefaultSigner(key)
+
+	var path string
+	client := apiClient(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_, _ = w.Write([]byte(`{"address": "0x300b105942d6d181cdfe8199fd48eb09d26efd24", "chain": "sepolia", "collection": "test", "contract_standard": "erc721"}`))
+	})
+	account, err := NewAccount(context.Background(),
+		WithContract("0x300b105942d6d181cdfe8199fd48eb09d26efd24"),
+		WithChain("sepolia"),
+		WithSigner(signer),
+		WithHTTPClient(client),
+		WithBackend(&chainBackend{chainID: 1337}),
+	)
+	require.Nil(t, err)
+	defer account.Close()
+	require.Equal(t, "/api/v2/chain/sepolia/contract/0x300b105942d6d181cdfe8199fd48eb09d26efd24", path)
+	require.Equal(t, "test", account.contract.Collection)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), account.WalletAddress())
+	require.Equal(t, int64(1337), account.chainID.Int64())
+	require.Equal(t, "sepolia", account.chain.Name)
+}
+
+func TestNewAccount_Errors(t *testing.T) {
+	t.Setenv("PRIVATE_KEY", "")
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	signer := WithSigner(wallet.NewDefaultSigner(key))
+	contract := WithContract("0x300b105942d6d181cdfe8199fd48eb09d26efd24")
+	backend := WithBackend(&chainBackend{chainID: 11155111})
+	notFound := WithHTTPClient(apiClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors": ["not found"]}`))
+	}))
+
+	cases := map[string]struct {
+		opts  []Option
+		stage string
+	}{
+		"no contract":      {[]Option{signer, backend}, StageOptions},
+		"bad chain":        {[]Option{contract, WithChain("moon"), signer, backend}, StageChain},
+		"no key":           {[]Option{contract, backend}, StageSigner},
+		"no rpc":           {[]Option{contract, signer, WithRPC("http://127.0.0.1:1")}, StageBackend},
+		"no contract info": {[]Option{contract, WithChain("sepolia"), signer, backend, notFound}, StageContract},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewAccount(context.Background(), c.opts...)
+			var accountErr *AccountError
+			require.True(t, errors.As(err, &accountErr), err)
+			require.Equal(t, c.stage, accountErr.Stage)
+			require.True(t, strings.HasPrefix(err.Error(), "new account: "+c.stage))
+		})
+	}
+}
+
+func TestHTTPAgent(t *testing.T) {
+	client := apiClient(func(w http.ResponseWriter, r *http.Request) {})
+	agent, err := httpAgent(client)
+	require.Nil(t, err)
+	require.Equal(t, client.Transport, agent.Transport)
+	require.NotSame(t, client, agent.Client)
+	require.False(t, gorequest.DisableTransportSwap)
+
+	_, err = httpAgent(&http.Client{Transport: roundTripFunc(http.DefaultTransport.RoundTrip)})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,109 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethersphere/bee/pkg/crypto/eip712"
+	"math/big"
+	"strings"
+)
+
+var ErrOrderHashMismatch = errors.New("order hash mismatch")
+
+// OrderHash computes the Seaport order hash of p, the EIP-712 struct hash of
+// its OrderComponents. It is what Seaport.getOrderHash returns and what OpenSea
+// indexes orders by.
+func (p *OrderParameters) OrderHash() (common.Hash, error) {
+	// the domain does not enter the struct hash but must not be empty
+	data := eip712.TypedData{Domain: eip712.TypedDataDomain{VerifyingContract: ProtocolAddress}}
+	if err := json.Unmarshal([]byte(types), &data.Types); err != nil {
+		return common.Hash{}, err
+	}
+	if _, ok := new(big.Int).SetString(p.Salt, 0); !ok {
+		return common.Hash{}, fmt.Errorf("invalid salt %q", p.Salt)
+	}
+	hash, err := data.HashStruct("OrderComponents", p.message())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// message is the EIP-712 OrderComponents message of p.
+func (p *OrderParameters) message() map[string]interface{} {
+	salt, _ := big.NewInt(0).SetString(p.Salt, 0)
+
+	offer := make([]interface{}, 0)
+	for _, item := range p.Offer {
+		offer = append(offer, map[string]interface{}{
+			"itemType":             big.NewInt(int64(item.ItemType)),
+			"token":                item.Token,
+			"identifierOrCriteria": bigOrZero(item.IdentifierOrCriteria),
+			"startAmount":          bigOrZero(item.StartAmount),
+			"endAmount":            bigOrZero(item.EndAmount),
+		})
+	}
+
+	consideration := make([]interface{}, 0)
+	for _, item := range p.Consideration {
+		consideration = append(consideration, map[string]interface{}{
+			"itemType":             big.NewInt(int64(item.ItemType)),
+			"token":                item.Token,
+			"identifierOrCriteria": bigOrZero(item.IdentifierOrCriteria),
+			"startAmount":          bigOrZero(item.StartAmount),
+			"endAmount":            bigOrZero(item.EndAmount),
+			"recipient":            item.Recipient,
+		})
+	}
+
+	return map[string]interface{}{
+		"offer":         offer,
+		"consideration": consideration,
+		"offerer":       p.Offerer,
+		"startTime":     big.NewInt(p.StartTime),
+		"endTime":       big.NewInt(p.EndTime),
+		"orderType":     big.NewInt(int64(p.OrderType)),
+		"zone":          p.Zone,
+		"zoneHash":      hexStringToByte32(p.ZoneHash),
+		"salt":          salt,
+		"conduitKey":    hexStringToByte32(p.ConduitKey),
+		"counter":       bigOrZero(p.Counter),
+	}
+}
+
+// verifiedOrderHash computes the order hash of p locally and checks it against
+// the Seaport contract.
+func (a *Account) verifiedOrderHash(p *OrderParameters) (string, error) {
+	local, err := p.OrderHash()
+	if err != nil {
+		return "", err
+	}
+
+	components, err := p.toOrderComponents()
+	if err != nil {
+		return "", err
+	}
+	onChain, err := a.seaportInstance.GetOrderHash(nil, *components)
+	if err != nil {
+		return "", err
+	}
+	if local != onChain {
+		return "", fmt.Errorf("%w: computed %s, seaport %s", ErrOrderHashMismatch, local.Hex(), common.Hash(onChain).Hex())
+	}
+	return hexutil.Encode(local[:]), nil
+}
+
+// checkOrderHash compares the hash OpenSea reports for a posted order with the
+// one computed when signing it.
+func checkOrderHash(expected, reported string) error {
+	if reported == "" {
+		return fmt.Errorf("%w: opensea returned no order hash for %s", ErrOrderHashMismatch, expected)
+	}
+	if !strings.EqualFold(expected, reported) {
+		return fmt.Errorf("%w: computed %s, opensea %s", ErrOrderHashMismatch, expected, reported)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+const (
+	offerItemType         = "OfferItem(uint8 itemType,address token,uint256 identifierOrCriteria,uint256 startAmount,uint256 endAmount)"
+	considerationItemType = "ConsiderationItem(uint8 itemType,address token,uint256 identifierOrCriteria,uint256 startAmount,uint256 endAmount,address recipient)"
+	orderComponentsType   = "OrderComponents(address offerer,address zone,OfferItem[] offer,ConsiderationItem[] consideration,uint8 orderType,uint256 startTime,uint256 endTime,bytes32 zoneHash,uint256 salt,bytes32 conduitKey,uint256 counter)"
+)
+
+func word(v interface{}) []byte {
+	switch v := v.(type) {
+	case int64:
+		return math.U256Bytes(big.NewInt(v))
+	case *big.Int:
+		return math.U256Bytes(new(big.Int).Set(v))
+	case string:
+		return common.LeftPadBytes(common.HexToAddress(v).Bytes(), 32)
+	case [32]byte:
+		return v[:]
+	case []byte:
+		return v
+	}
+	panic("unsupported word")
+}
+
+func keccak(words ...interface{}) []byte {
+	data := make([]byte, 0)
+	for _, w := range words {
+		data = append(data, word(w)...)
+	}
+	return crypto.Keccak256(data)
+}
+
+// seaportOrderHash mirrors Seaport's _deriveOrderHash.
+func seaportOrderHash(p *OrderParameters, salt int64) common.Hash {
+	offerHashes := make([]interface{}, 0)
+	for _, item := range p.Offer {
+		offerHashes = append(offerHashes, keccak(crypto.Keccak256([]byte(offerItemType)), int64(item.ItemType),
+			item.Token, item.IdentifierOrCriteria, item.StartAmount, item.EndAmount))
+	}
+	considerationHashes := make([]interface{}, 0)
+	for _, item := range p.Consideration {
+		considerationHashes = append(considerationHashes, keccak(crypto.Keccak256([]byte(considerationItemType)), int64(item.ItemType),
+			item.Token, item.IdentifierOrCriteria, item.StartAmount, item.EndAmount, item.Recipient))
+	}
+	typeHash := crypto.Keccak256([]byte(orderComponentsType + considerationItemType + offerItemType))
+	return common.BytesToHash(keccak(typeHash, p.Offerer, p.Zone, keccak(offerHashes...), keccak(considerationHashes...),
+		int64(p.OrderType), p.StartTime, p.EndTime, hexStringToByte32(p.ZoneHash), salt,
+		hexStringToByte32(p.ConduitKey), p.Counter))
+}
+
+func TestOrderParameters_OrderHash(t *testing.T) {
+	offerer := "0x9d1E5C9bA1c7B8fB8a4fA3e6d1DA6e8a25E4b4A2"
+	// 975 + 25 ETH and a 256-bit token id, both far beyond int64
+	proceeds, _ := new(big.Int).SetString("975000000000000000000", 10)
+	fee, _ := new(big.Int).SetString("25000000000000000000", 10)
+	identifier := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	p := &OrderParameters{
+		Offerer:    offerer,
+		Zone:       zeroAddress().Hex(),
+		ZoneHash:   zero32BytesHexString(),
+		StartTime:  1700000000,
+		EndTime:    1700086400,
+		OrderType:  0,
+		Salt:       "1700000000",
+		ConduitKey: SeaportConduitKey,
+		Offer: []OfferItem{
+			{ItemType: 2, Token: "0x300b105942d6d181cdfe8199fd48eb09d26efd24", IdentifierOrCriteria: identifier, StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)},
+		},
+		Consideration: []ConsiderationItem{
+			{ItemType: 0, Token: zeroAddress().Hex(), IdentifierOrCriteria: new(big.Int), StartAmount: proceeds, EndAmount: proceeds, Recipient: offerer},
+			{ItemType: 0, Token: zeroAddress().Hex(), IdentifierOrCriteria: new(big.Int), StartAmount: fee, EndAmount: fee, Recipient: "0x0000a26b00c1F0DF003000390027140000fAa719"},
+		},
+		TotalOriginalConsiderationItems: 2,
+		Counter:                         big.NewInt(3),
+	}
+
+	hash, err := p.OrderHash()
+	require.Nil(t, err)
+	require.Equal(t, seaportOrderHash(p, 1700000000), hash)
+
+	p.Counter = big.NewInt(4)
+	changed, err := p.OrderHash()
+	require.Nil(t, err)
+	require.NotEqual(t, hash, changed)
+
+	p.Salt = "salt"
+	_, err = p.OrderHash()
+	require.NotNil(t, err)
+}
+
+func TestCheckOrderHash(t *testing.T) {
+	hash := "0x8a7c1b2d3e4f5061728394a5b6c7d8e9f0a1b2c3d4e5f60718293a4b5c6d7e8f"
+	require.Nil(t, checkOrderHash(hash, "0x8A7C1B2D3E4F5061728394A5B6C7D8E9F0A1B2C3D4E5F60718293A4B5C6D7E8F"))
+	require.ErrorIs(t, checkOrderHash(hash, ""), ErrOrderHashMismatch)
+	require.ErrorIs(t, checkOrderHash(hash, "0x01"), ErrOrderHashMismatch)
+}
@@ -0,0 +1,179 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"log"
+	"math/big"
+	"opensea-bot/pkg/seaport"
+	"strings"
+	"time"
+)
+
+func (a *Account) SetOrderStore(store *OrderStore) {
+	a.store = store
+	if a.txs != nil {
+		a.txs.SetJournal(store)
+	}
+}
+
+// trackOrder returns the verified hash of a freshly signed order and records
+// the order as created when an order store is configured. It runs before the
+// order is posted so that an order whose post times out can still be found and
+// cancelled.
+func (a *Account) trackOrder(side string, data *protocolData, price string) (string, error) {
+	orderHash, err := a.verifiedOrderHash(&data.Parameters)
+	if err != nil {
+		return "", err
+	}
+	if a.store == nil {
+		return orderHash, nil
+	}
+	return orderHash, a.store.Put(&StoredOrder{
+		OrderHash:  orderHash,
+		Chain:      a.contract.Chain,
+		Side:       side,
+		Parameters: data.Parameters,
+		Signature:  data.Signature,
+		Price:      price,
+		ExpireAt:   data.Parameters.EndTime,
+		Status:     OrderCreated,
+	})
+}
+
+func (a *Account) markOrder(orderHash string, status OrderStatus) {
+	if a.store == nil || orderHash == "" {
+		return
+	}
+	if err := a.store.UpdateStatus(orderHash, status); err != nil && !errors.Is(err, ErrOrderNotFound) {
+		log.Printf("update order %s to %s: %v", orderHash, status, err)
+	}
+}
+
+// markCancelled flags the stored orders cancelled by the OrderCancelled events
+// of receipt.
+func (a *Account) markCancelled(receipt *ethtypes.Receipt) {
+	for _, l := range receipt.Logs {
+		event, err := a.seaportInstance.ParseOrderCancelled(*l)
+		if err != nil {
+			continue
+		}
+		a.markOrder(hexutil.Encode(event.OrderHash[:]), OrderCancelled)
+	}
+}
+
+// ReconcileOrders refreshes the status of the open orders of the account in the
+// store from the Seaport contract and from OpenSea.
+func (a *Account) ReconcileOrders(ctx context.Context) error {
+	if a.store == nil {
+		return errors.New("no order store configured")
+	}
+	orders, err := a.store.List(OrderCreated, OrderActive)
+	if err != nil {
+		return err
+	}
+
+	counter, err := a.seaportInstance.GetCounter(nil, a.WalletAddress())
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		if order.Chain != a.contract.Chain || !strings.EqualFold(order.Parameters.Offerer, a.WalletAddress().Hex()) {
+			continue
+		}
+		status, err := a.orderStatus(ctx, order, counter)
+		if err != nil {
+			log.Printf("reconcile order %s: %v", order.OrderHash, err)
+			continue
+		}
+		if status != order.Status {
+			log.Printf("order %s %s -> %s", order.OrderHash, order.Status, status)
+			a.markOrder(order.OrderHash, status)
+		}
+	}
+	return nil
+}
+
+func (a *Account) orderStatus(ctx context.Context, order *StoredOrder, counter *big.Int) (OrderStatus, error) {
+	if bigOrZero(order.Parameters.Counter).Cmp(counter) < 0 {
+		return OrderCancelled, nil
+	}
+
+	onChain, err := a.seaportInstance.GetOrderStatus(nil, common.HexToHash(order.OrderHash))
+	if err != nil {
+		return "", err
+	}
+	if onChain.IsCancelled {
+		return OrderCancelled, nil
+	}
+	if onChain.TotalSize.Sign() > 0 && onChain.TotalFilled.Cmp(onChain.TotalSize) >= 0 {
+		return OrderFilled, nil
+	}
+	if order.ExpireAt > 0 && order.ExpireAt <= time.Now().Unix() {
+		return OrderExpired, nil
+	}
+
+	remote, err := a.GetOrder(ctx, order.OrderHash)
+	if errors.Is(err, ErrUnknownOrder) {
+		// OpenSea never accepted the order, it is not live there
+		return order.Status, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case remote.Cancelled, remote.MarkedInvalid:
+		return OrderCancelled, nil
+	case remote.Finalized:
+		return OrderFilled, nil
+	}
+	return OrderActive, nil
+}
+
+func (p *OrderParameters) toOrderComponents() (*seaport.OrderComponents, error) {
+	salt, ok := new(big.Int).SetString(p.Salt, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid salt %q", p.Salt)
+	}
+
+	offer := make([]seaport.OfferItem, 0, len(p.Offer))
+	for _, item := range p.Offer {
+		offer = append(offer, seaport.OfferItem{
+			ItemType:             item.ItemType,
+			Token:                common.HexToAddress(item.Token),
+			IdentifierOrCriteria: bigOrZero(item.IdentifierOrCriteria),
+			StartAmount:          bigOrZero(item.StartAmount),
+			EndAmount:            bigOrZero(item.EndAmount),
+		})
+	}
+	consideration := make([]seaport.ConsiderationItem, 0, len(p.Consideration))
+	for _, item := range p.Consideration {
+		consideration = append(consideration, seaport.ConsiderationItem{
+			ItemType:             item.ItemType,
+			Token:                common.HexToAddress(item.Token),
+			IdentifierOrCriteria: bigOrZero(item.IdentifierOrCriteria),
+			StartAmount:          bigOrZero(item.StartAmount),
+			EndAmount:            bigOrZero(item.EndAmount),
+			Recipient:            common.HexToAddress(item.Recipient),
+		})
+	}
+
+	return &seaport.OrderComponents{
+		Offerer:       common.HexToAddress(p.Offerer),
+		Zone:          common.HexToAddress(p.Zone),
+		Offer:         offer,
+		Consideration: consideration,
+		OrderType:     p.OrderType,
+		StartTime:     big.NewInt(p.StartTime),
+		EndTime:       big.NewInt(p.EndTime),
+		ZoneHash:      hexStringToByte32(p.ZoneHash),
+		Salt:          salt,
+		ConduitKey:    hexStringToByte32(p.ConduitKey),
+		Counter:       bigOrZero(p.Counter),
+	}, nil
+}
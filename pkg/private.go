@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"strings"
+)
+
+// CreatePrivateListing lists nft at price for taker only. The NFT is added as
+// a consideration item paid to taker, so the order can only be fulfilled by
+// matching it with an order of taker's.
+func (a *Account) CreatePrivateListing(ctx context.Context, nft *NFT, taker, price string, expire int) (*CreateListingResp, error) {
+	address, err := parseTaker(taker)
+	if err != nil {
+		return nil, err
+	}
+	if address == a.WalletAddress() {
+		return nil, errors.New("taker is the offerer")
+	}
+	return a.createListing(ctx, nft, 1, price, price, expire, address)
+}
+
+// privateSaleItem is the consideration item sending the listed items to taker.
+func privateSaleItem(offer OfferItem, taker common.Address) ConsiderationItem {
+	return ConsiderationItem{
+		ItemType:             offer.ItemType,
+		Token:                offer.Token,
+		IdentifierOrCriteria: offer.IdentifierOrCriteria,
+		StartAmount:          offer.StartAmount,
+		EndAmount:            offer.EndAmount,
+		Recipient:            taker.Hex(),
+	}
+}
+
+// parseTaker validates a taker address: 20 hex bytes, not the zero address and
+// with a valid EIP-55 checksum when written in mixed case.
+func parseTaker(taker string) (common.Address, error) {
+	if !common.IsHexAddress(taker) || !strings.HasPrefix(taker, "0x") {
+		return common.Address{}, fmt.Errorf("invalid taker address %q", taker)
+	}
+	address := common.HexToAddress(taker)
+	if address == zeroAddress() {
+		return common.Address{}, errors.New("taker is the zero address")
+	}
+	hex := strings.TrimPrefix(taker, "0x")
+	if hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) && taker != address.Hex() {
+		return common.Address{}, fmt.Errorf("taker address %q has an invalid checksum", taker)
+	}
+	return address, nil
+}
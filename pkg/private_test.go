@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestParseTaker(t *testing.T) {
+	address, err := parseTaker("0x8ba1f109551bD432803012645Ac136ddd64DBA72")
+	require.Nil(t, err)
+	require.Equal(t, common.HexToAddress("0x8ba1f109551bd432803012645ac136ddd64dba72"), address)
+
+	_, err = parseTaker("0x8ba1f109551bd432803012645ac136ddd64dba72")
+	require.Nil(t, err)
+
+	for _, taker := range []string{
+		"",
+		"8ba1f109551bd432803012645ac136ddd64dba72",
+		"0x8ba1f109551bd432803012645ac136ddd64dba",
+		"0x8ba1f109551bd432803012645ac136ddd64dbazz",
+		"0x8Ba1f109551bD432803012645Ac136ddd64DBA72",
+		"0x0000000000000000000000000000000000000000",
+	} {
+		_, err := parseTaker(taker)
+		require.NotNil(t, err, taker)
+	}
+}
+
+func TestPrivateSaleItem(t *testing.T) {
+	taker := common.HexToAddress("0x8ba1f109551bD432803012645Ac136ddd64DBA72")
+	offer := OfferItem{ItemType: 2, Token: "0x300b105942D6d181cdFE8199fD48eB09d26efd24", IdentifierOrCriteria: big.NewInt(42), StartAmount: big.NewInt(1), EndAmount: big.NewInt(1)}
+
+	item := privateSaleItem(offer, taker)
+	require.Equal(t, uint8(2), item.ItemType)
+	require.Equal(t, offer.Token, item.Token)
+	require.Equal(t, int64(42), item.IdentifierOrCriteria.Int64())
+	require.Equal(t, int64(1), item.EndAmount.Int64())
+	require.Equal(t, taker.Hex(), item.Recipient)
+}
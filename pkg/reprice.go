@@ -0,0 +1,296 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"log"
+	"strings"
+	"time"
+)
+
+// RepriceRule bounds the price the repricer lists an NFT at.
+type RepriceRule struct {
+	// Tick is how far under the cheapest competing listing to list.
+	Tick decimal.Decimal
+	// Ceiling is the price used when there is no competing listing and the
+	// highest price ever listed at. Zero means no ceiling.
+	Ceiling decimal.Decimal
+	// Margin is the profit over the last sale cost, after fees, to keep.
+	Margin decimal.Decimal
+	// MinPrice is an absolute floor, applied on top of the cost based one.
+	MinPrice decimal.Decimal
+}
+
+// Reprice lists nft one tick under the cheapest listing of the collection that
+// is not ours, never under the cost based floor of the rule. Our listings priced
+// under the new one are cancelled before listing so no stale cheaper order is
+// left.
+func (a *Account) Reprice(ctx context.Context, nft *NFT, rule RepriceRule, expire int) error {
+	floor, err := a.competingFloor(ctx)
+	if err != nil {
+		return err
+	}
+
+	minimum, err := a.minimumPrice(ctx, nft, rule)
+	if err != nil {
+		return err
+	}
+
+	target := repriceTarget(floor, rule.Ceiling, minimum, rule.Tick)
+	if !target.IsPositive() {
+		return fmt.Errorf("no price for %s #%s: no competing listing and no ceiling", nft.Contract, nft.Identifier)
+	}
+
+	if a.store == nil {
+		return a.repriceUntracked(ctx, nft, target, expire)
+	}
+	listings, err := a.openListings(nft)
+	if err != nil {
+		return err
+	}
+	listed := false
+	var stale []*StoredOrder
+	for _, listing := range listings {
+		price, err := listingPrice(listing)
+		if err != nil {
+			return fmt.Errorf("listing %s: %w", listing.OrderHash, err)
+		}
+		switch {
+		case price.Equal(target):
+			listed = true
+		case price.LessThan(target):
+			stale = append(stale, listing)
+		}
+	}
+	if len(stale) > 0 {
+		log.Printf("raise %s #%s to %s, cancel %d cheaper listings first", nft.Contract, nft.Identifier, target, len(stale))
+		if _, err := a.cancelStored(ctx, stale); err != nil {
+			return fmt.Errorf("cancel stale listings: %w", err)
+		}
+	}
+	if listed {
+		return nil
+	}
+	log.Printf("reprice %s #%s to %s", nft.Contract, nft.Identifier, target)
+	_, err = a.CreateListing(ctx, nft, target.String(), expire)
+	return err
+}
+
+// repriceUntracked reprices without an order store, only our cheapest listing
+// OpenSea reports is known.
+func (a *Account) repriceUntracked(ctx context.Context, nft *NFT, target decimal.Decimal, expire int) error {
+	current, err := a.ourListing(ctx, nft)
+	if err != nil {
+		return err
+	}
+	superseded := false
+	if current != nil {
+		currentPrice, err := current.CurrentPrice()
+		if err != nil {
+			return err
+		}
+		if currentPrice.Equal(target) {
+			return nil
+		}
+		if currentPrice.LessThan(target) {
+			log.Printf("raise %s #%s from %s to %s, cancel %s first", nft.Contract, nft.Identifier, currentPrice, target, current.OrderHash)
+			if err := a.cancelListing(ctx, current); err != nil {
+				return fmt.Errorf("cancel stale listing %s: %w", current.OrderHash, err)
+			}
+		} else {
+			superseded = true
+		}
+	}
+
+	log.Printf("reprice %s #%s to %s", nft.Contract, nft.Identifier, target)
+	if _, err := a.CreateListing(ctx, nft, target.String(), expire); err != nil {
+		return err
+	}
+
+	// a superseded listing is more expensive than the new one, only bother
+	// cancelling it when that is free
+	if superseded && hasZone(&current.ProtocolData.Parameters) {
+		if _, err := a.cancelOffChain(ctx, current.OrderHash); err != nil {
+			log.Printf("cancel superseded listing %s: %v", current.OrderHash, err)
+		}
+	}
+	return nil
+}
+
+// openListings returns the unexpired listings of nft by the account that the
+// order store knows to be open.
+func (a *Account) openListings(nft *NFT) ([]*StoredOrder, error) {
+	id, err := nft.identifier()
+	if err != nil {
+		return nil, err
+	}
+	orders, err := a.store.List(OrderCreated, OrderActive)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	listings := make([]*StoredOrder, 0)
+	for _, order := range orders {
+		if order.Side != SideListing || order.Chain != a.contract.Chain || order.ExpireAt <= now ||
+			!strings.EqualFold(order.Parameters.Offerer, a.WalletAddress().Hex()) || len(order.Parameters.Offer) != 1 {
+			continue
+		}
+		item := order.Parameters.Offer[0]
+		if common.HexToAddress(item.Token) == common.HexToAddress(nft.Contract) && bigOrZero(item.IdentifierOrCriteria).Cmp(id) == 0 {
+			listings = append(listings, order)
+		}
+	}
+	return listings, nil
+}
+
+// listingPrice is the unit price a stored listing was posted at, the end
+// price of a dutch auction.
+func listingPrice(order *StoredOrder) (decimal.Decimal, error) {
+	price := order.Price
+	if i := strings.LastIndex(price, "->"); i >= 0 {
+		price = price[i+2:]
+	}
+	return decimal.NewFromString(price)
+}
+
+// competingFloor returns the cheapest listing price of the collection that is
+// not offered by the account, or nil when there is none.
+func (a *Account) competingFloor(ctx context.Context) (*decimal.Decimal, error) {
+	listings, err := a.GetBestListing(ctx, 20)
+	if err != nil {
+		return nil, err
+	}
+
+	var floor *decimal.Decimal
+	for i := range listings {
+		if strings.EqualFold(listings[i].ProtocolData.Parameters.Offerer, a.WalletAddress().Hex()) {
+			continue
+		}
+		price, err := listings[i].CurrentPrice()
+		if err != nil {
+			continue
+		}
+		if floor == nil || price.LessThan(*floor) {
+			floor = &price
+		}
+	}
+	return floor, nil
+}
+
+// minimumPrice is the lowest price at which selling nft still returns its last
+// sale cost plus the rule's margin once the collection fees are paid.
+func (a *Account) minimumPrice(ctx context.Context, nft *NFT, rule RepriceRule) (decimal.Decimal, error) {
+	minimum := rule.MinPrice
+
+	cost, err := a.lastSaleCost(nft)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if cost == nil {
+		return decimal.Max(minimum, rule.Margin), nil
+	}
+	quantity, err := decimal.NewFromString(cost.Quantity)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	collection, err := a.GetCollection(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.Max(minimum, breakEvenPrice(quantity.Shift(int32(-cost.Decimals)), collection.requiredFeePercent(), rule.Margin)), nil
+}
+
+func (a *Account) ourListing(ctx context.Context, nft *NFT) (*BestListingResp, error) {
+	listing, err := a.GetBestListingByNFT(ctx, nft.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	if listing == nil || listing.OrderHash == "" ||
+		!strings.EqualFold(listing.ProtocolData.Parameters.Offerer, a.WalletAddress().Hex()) {
+		return nil, nil
+	}
+	return listing, nil
+}
+
+// cancelListing cancels through OpenSea when the listing uses its zone and
+// on-chain otherwise.
+func (a *Account) cancelListing(ctx context.Context, listing *BestListingResp) error {
+	if hasZone(&listing.ProtocolData.Parameters) {
+		_, err := a.cancelOffChain(ctx, listing.OrderHash)
+		return err
+	}
+	_, err := a.CancelOrders(ctx, []Parameters{listing.ProtocolData.Parameters})
+	return err
+}
+
+func (c *CollectionResp) requiredFeePercent() decimal.Decimal {
+	total := decimal.Zero
+	for _, fee := range c.Fees {
+		if fee.Required {
+			total = total.Add(decimal.NewFromFloat(fee.Fee))
+		}
+	}
+	return total
+}
+
+func hasZone(p *Parameters) bool {
+	return p.Zone != "" && !strings.EqualFold(p.Zone, zeroAddress().Hex())
+}
+
+// breakEvenPrice returns the price whose proceeds after feePercent cover cost
+// plus margin.
+func breakEvenPrice(cost, feePercent, margin decimal.Decimal) decimal.Decimal {
+	keep := decimal.NewFromInt(1).Sub(feePercent.Div(decimal.NewFromInt(100)))
+	if !keep.IsPositive() {
+		return decimal.Zero
+	}
+	return cost.Add(margin).DivRound(keep, 18)
+}
+
+// repriceTarget is one tick under floor, capped by ceiling when set and never
+// under minimum. Without a floor the ceiling is used.
+func repriceTarget(floor *decimal.Decimal, ceiling, minimum, tick decimal.Decimal) decimal.Decimal {
+	target := ceiling
+	if floor != nil {
+		under := floor.Sub(tick)
+		if ceiling.IsZero() || under.LessThan(ceiling) {
+			target = under
+		}
+	}
+	if target.LessThan(minimum) {
+		target = minimum
+	}
+	return target
+}
+
+func (c *RepriceConfig) rule(ceiling string) (RepriceRule, error) {
+	var rule RepriceRule
+	var err error
+	if rule.Tick, err = parsePositive(c.Tick); err != nil {
+		return rule, fmt.Errorf("tick: %w", err)
+	}
+	for _, v := range []struct {
+		value string
+		dst   *decimal.Decimal
+		name  string
+	}{
+		{ceiling, &rule.Ceiling, "price"},
+		{c.Margin, &rule.Margin, "margin"},
+		{c.MinPrice, &rule.MinPrice, "min_price"},
+	} {
+		if v.value == "" {
+			continue
+		}
+		if *v.dst, err = decimal.NewFromString(v.value); err != nil {
+			return rule, fmt.Errorf("%s: %w", v.name, err)
+		}
+		if v.dst.IsNegative() {
+			return rule, errors.New(v.name + " must not be negative")
+		}
+	}
+	return rule, nil
+}
@@ -0,0 +1,79 @@
+package pkg
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"opensea-bot/pkg/seaport"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRepriceTarget(t *testing.T) {
+	d := decimal.RequireFromString
+	floor := d("1.5")
+	tick := d("0.01")
+
+	require.Equal(t, "1.49", repriceTarget(&floor, decimal.Zero, decimal.Zero, tick).String())
+	require.Equal(t, "1.2", repriceTarget(&floor, d("1.2"), decimal.Zero, tick).String())
+	require.Equal(t, "1.6", repriceTarget(&floor, d("2"), d("1.6"), tick).String())
+	require.Equal(t, "2", repriceTarget(nil, d("2"), d("1"), tick).String())
+	require.True(t, repriceTarget(nil, decimal.Zero, decimal.Zero, tick).IsZero())
+}
+
+func TestBreakEvenPrice(t *testing.T) {
+	d := decimal.RequireFromString
+	require.Equal(t, "1.1", breakEvenPrice(d("0.99"), d("10"), decimal.Zero).String())
+	require.Equal(t, "1.05", breakEvenPrice(d("1"), decimal.Zero, d("0.05")).String())
+	require.True(t, breakEvenPrice(d("1"), d("100"), decimal.Zero).IsZero())
+}
+
+func TestOpenListings(t *testing.T) {
+	store, err := OpenOrderStore(filepath.Join(t.TempDir(), "store"))
+	require.Nil(t, err)
+	defer store.Close()
+	backend := &sendingBackend{}
+	account := testAccount(t, backend, nil)
+	account.seaportInstance, err = seaport.NewSeaport(common.HexToAddress(account.chain.SeaportAddress), backend)
+	require.Nil(t, err)
+	account.SetOrderStore(store)
+
+	listing := func(hash, price string, identifier int64, status OrderStatus) *StoredOrder {
+		return &StoredOrder{OrderHash: hash, Chain: "sepolia", Side: SideListing, Price: price, Status: status,
+			ExpireAt: time.Now().Add(time.Hour).Unix(),
+			Parameters: OrderParameters{Offerer: account.WalletAddress().Hex(), Salt: fixedSalt(), Counter: new(big.Int),
+				Offer: []OfferItem{{ItemType: 2, Token: testContract, IdentifierOrCriteria: big.NewInt(identifier)}}}}
+	}
+	for _, order := range []*StoredOrder{
+		listing("0x09", "9", 7, OrderActive),
+		listing("0x08", "8", 7, OrderActive),
+		listing("0x07", "10->7", 7, OrderCreated),
+		listing("0x10", "10", 7, OrderCancelled),
+		listing("0x11", "5", 8, OrderActive),
+	} {
+		require.Nil(t, store.Put(order))
+	}
+
+	listings, err := account.openListings(account.NFT("7"))
+	require.Nil(t, err)
+	prices := map[string]string{}
+	for _, order := range listings {
+		price, err := listingPrice(order)
+		require.Nil(t, err)
+		prices[order.OrderHash] = price.String()
+	}
+	require.Equal(t, map[string]string{"0x09": "9", "0x08": "8", "0x07": "7"}, prices)
+
+	// the stale listings go in one transaction
+	_, err = account.cancelStored(context.Background(), listings)
+	require.Nil(t, err)
+	require.Len(t, backend.sent, 1)
+	seaportABI, err := seaport.SeaportMetaData.GetAbi()
+	require.Nil(t, err)
+	args, err := seaportABI.Methods["cancel"].Inputs.Unpack(backend.sent[0].Data()[4:])
+	require.Nil(t, err)
+	require.Len(t, args[0], 3)
+}
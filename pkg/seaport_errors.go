@@ -0,0 +1,301 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// Seaport custom errors without arguments, a RevertError unwraps to them.
+var (
+	ErrBadContractSignature                       = errors.New("seaport: BadContractSignature")
+	ErrBadFraction                                = errors.New("seaport: BadFraction")
+	ErrCannotCancelOrder                          = errors.New("seaport: CannotCancelOrder")
+	ErrConsiderationCriteriaResolverOutOfRange    = errors.New("seaport: ConsiderationCriteriaResolverOutOfRange")
+	ErrConsiderationLengthNotEqualToTotalOriginal = errors.New("seaport: ConsiderationLengthNotEqualToTotalOriginal")
+	ErrCriteriaNotEnabledForItem                  = errors.New("seaport: CriteriaNotEnabledForItem")
+	ErrInexactFraction                            = errors.New("seaport: InexactFraction")
+	ErrInsufficientNativeTokensSupplied           = errors.New("seaport: InsufficientNativeTokensSupplied")
+	ErrInvalid1155BatchTransferEncoding           = errors.New("seaport: Invalid1155BatchTransferEncoding")
+	ErrInvalidBasicOrderParameterEncoding         = errors.New("seaport: InvalidBasicOrderParameterEncoding")
+	ErrInvalidFulfillmentComponentData            = errors.New("seaport: InvalidFulfillmentComponentData")
+	ErrInvalidNativeOfferItem                     = errors.New("seaport: InvalidNativeOfferItem")
+	ErrInvalidProof                               = errors.New("seaport: InvalidProof")
+	ErrInvalidSignature                           = errors.New("seaport: InvalidSignature")
+	ErrInvalidSigner                              = errors.New("seaport: InvalidSigner")
+	ErrMissingItemAmount                          = errors.New("seaport: MissingItemAmount")
+	ErrMissingOriginalConsiderationItems          = errors.New("seaport: MissingOriginalConsiderationItems")
+	ErrNoReentrantCalls                           = errors.New("seaport: NoReentrantCalls")
+	ErrNoSpecifiedOrdersAvailable                 = errors.New("seaport: NoSpecifiedOrdersAvailable")
+	ErrOfferAndConsiderationRequiredOnFulfillment = errors.New("seaport: OfferAndConsiderationRequiredOnFulfillment")
+	ErrOfferCriteriaResolverOutOfRange            = errors.New("seaport: OfferCriteriaResolverOutOfRange")
+	ErrPartialFillsNotEnabledForOrder             = errors.New("seaport: PartialFillsNotEnabledForOrder")
+	ErrUnusedItemParameters                       = errors.New("seaport: UnusedItemParameters")
+)
+
+// Seaport custom errors with arguments. Field names follow the ABI, match them
+// with errors.As.
+
+type BadReturnValueFromERC20OnTransferError struct {
+	Token, From, To common.Address
+	Amount          *big.Int
+}
+
+func (e *BadReturnValueFromERC20OnTransferError) Error() string {
+	return fmt.Sprintf("seaport: BadReturnValueFromERC20OnTransfer(token %s, from %s, to %s, amount %s)",
+		e.Token.Hex(), e.From.Hex(), e.To.Hex(), e.Amount)
+}
+
+type BadSignatureVError struct {
+	V uint8
+}
+
+func (e *BadSignatureVError) Error() string {
+	return fmt.Sprintf("seaport: BadSignatureV(%d)", e.V)
+}
+
+type ConsiderationNotMetError struct {
+	OrderIndex, ConsiderationIndex, ShortfallAmount *big.Int
+}
+
+func (e *ConsiderationNotMetError) Error() string {
+	return fmt.Sprintf("seaport: ConsiderationNotMet(order %s, consideration %s, shortfall %s)",
+		e.OrderIndex, e.ConsiderationIndex, e.ShortfallAmount)
+}
+
+type ERC1155BatchTransferGenericFailureError struct {
+	Token, From, To      common.Address
+	Identifiers, Amounts []*big.Int
+}
+
+func (e *ERC1155BatchTransferGenericFailureError) Error() string {
+	return fmt.Sprintf("seaport: ERC1155BatchTransferGenericFailure(token %s, from %s, to %s, identifiers %v, amounts %v)",
+		e.Token.Hex(), e.From.Hex(), e.To.Hex(), e.Identifiers, e.Amounts)
+}
+
+type InvalidCallToConduitError struct {
+	Conduit common.Address
+}
+
+func (e *InvalidCallToConduitError) Error() string {
+	return fmt.Sprintf("seaport: InvalidCallToConduit(%s)", e.Conduit.Hex())
+}
+
+type InvalidConduitError struct {
+	ConduitKey common.Hash
+	Conduit    common.Address
+}
+
+func (e *InvalidConduitError) Error() string {
+	return fmt.Sprintf("seaport: InvalidConduit(key %s, conduit %s)", e.ConduitKey.Hex(), e.Conduit.Hex())
+}
+
+type InvalidContractOrderError struct {
+	OrderHash common.Hash
+}
+
+func (e *InvalidContractOrderError) Error() string {
+	return fmt.Sprintf("seaport: InvalidContractOrder(%s)", e.OrderHash.Hex())
+}
+
+type InvalidERC721TransferAmountError struct {
+	Amount *big.Int
+}
+
+func (e *InvalidERC721TransferAmountError) Error() string {
+	return fmt.Sprintf("seaport: InvalidERC721TransferAmount(%s)", e.Amount)
+}
+
+type InvalidMsgValueError struct {
+	Value *big.Int
+}
+
+func (e *InvalidMsgValueError) Error() string {
+	return fmt.Sprintf("seaport: InvalidMsgValue(%s)", e.Value)
+}
+
+type InvalidRestrictedOrderError struct {
+	OrderHash common.Hash
+}
+
+func (e *InvalidRestrictedOrderError) Error() string {
+	return fmt.Sprintf("seaport: InvalidRestrictedOrder(%s)", e.OrderHash.Hex())
+}
+
+type InvalidTimeError struct {
+	StartTime, EndTime *big.Int
+}
+
+func (e *InvalidTimeError) Error() string {
+	return fmt.Sprintf("seaport: InvalidTime(start %s, end %s)", e.StartTime, e.EndTime)
+}
+
+type MismatchedFulfillmentOfferAndConsiderationComponentsError struct {
+	FulfillmentIndex *big.Int
+}
+
+func (e *MismatchedFulfillmentOfferAndConsiderationComponentsError) Error() string {
+	return fmt.Sprintf("seaport: MismatchedFulfillmentOfferAndConsiderationComponents(%s)", e.FulfillmentIndex)
+}
+
+// Side is 0 for the offer, 1 for the consideration.
+type MissingFulfillmentComponentOnAggregationError struct {
+	Side uint8
+}
+
+func (e *MissingFulfillmentComponentOnAggregationError) Error() string {
+	return fmt.Sprintf("seaport: MissingFulfillmentComponentOnAggregation(side %d)", e.Side)
+}
+
+type NativeTokenTransferGenericFailureError struct {
+	Account common.Address
+	Amount  *big.Int
+}
+
+func (e *NativeTokenTransferGenericFailureError) Error() string {
+	return fmt.Sprintf("seaport: NativeTokenTransferGenericFailure(account %s, amount %s)", e.Account.Hex(), e.Amount)
+}
+
+type NoContractError struct {
+	Account common.Address
+}
+
+func (e *NoContractError) Error() string {
+	return fmt.Sprintf("seaport: NoContract(%s)", e.Account.Hex())
+}
+
+type OrderAlreadyFilledError struct {
+	OrderHash common.Hash
+}
+
+func (e *OrderAlreadyFilledError) Error() string {
+	return fmt.Sprintf("seaport: OrderAlreadyFilled(%s)", e.OrderHash.Hex())
+}
+
+type OrderCriteriaResolverOutOfRangeError struct {
+	Side uint8
+}
+
+func (e *OrderCriteriaResolverOutOfRangeError) Error() string {
+	return fmt.Sprintf("seaport: OrderCriteriaResolverOutOfRange(side %d)", e.Side)
+}
+
+type OrderIsCancelledError struct {
+	OrderHash common.Hash
+}
+
+func (e *OrderIsCancelledError) Error() string {
+	return fmt.Sprintf("seaport: OrderIsCancelled(%s)", e.OrderHash.Hex())
+}
+
+type OrderPartiallyFilledError struct {
+	OrderHash common.Hash
+}
+
+func (e *OrderPartiallyFilledError) Error() string {
+	return fmt.Sprintf("seaport: OrderPartiallyFilled(%s)", e.OrderHash.Hex())
+}
+
+type TokenTransferGenericFailureError struct {
+	Token, From, To    common.Address
+	Identifier, Amount *big.Int
+}
+
+func (e *TokenTransferGenericFailureError) Error() string {
+	return fmt.Sprintf("seaport: TokenTransferGenericFailure(token %s, from %s, to %s, identifier %s, amount %s)",
+		e.Token.Hex(), e.From.Hex(), e.To.Hex(), e.Identifier, e.Amount)
+}
+
+type UnresolvedConsiderationCriteriaError struct {
+	OrderIndex, ConsiderationIndex *big.Int
+}
+
+func (e *UnresolvedConsiderationCriteriaError) Error() string {
+	return fmt.Sprintf("seaport: UnresolvedConsiderationCriteria(order %s, consideration %s)", e.OrderIndex, e.ConsiderationIndex)
+}
+
+type UnresolvedOfferCriteriaError struct {
+	OrderIndex, OfferIndex *big.Int
+}
+
+func (e *UnresolvedOfferCriteriaError) Error() string {
+	return fmt.Sprintf("seaport: UnresolvedOfferCriteria(order %s, offer %s)", e.OrderIndex, e.OfferIndex)
+}
+
+// seaportErrors returns the Go error of each Seaport ABI error, a sentinel or
+// a new value for the arguments to be copied into.
+var seaportErrors = map[string]func() error{
+	"BadContractSignature":                       func() error { return ErrBadContractSignature },
+	"BadFraction":                                func() error { return ErrBadFraction },
+	"CannotCancelOrder":                          func() error { return ErrCannotCancelOrder },
+	"ConsiderationCriteriaResolverOutOfRange":    func() error { return ErrConsiderationCriteriaResolverOutOfRange },
+	"ConsiderationLengthNotEqualToTotalOriginal": func() error { return ErrConsiderationLengthNotEqualToTotalOriginal },
+	"CriteriaNotEnabledForItem":                  func() error { return ErrCriteriaNotEnabledForItem },
+	"InexactFraction":                            func() error { return ErrInexactFraction },
+	"InsufficientNativeTokensSupplied":           func() error { return ErrInsufficientNativeTokensSupplied },
+	"Invalid1155BatchTransferEncoding":           func() error { return ErrInvalid1155BatchTransferEncoding },
+	"InvalidBasicOrderParameterEncoding":         func() error { return ErrInvalidBasicOrderParameterEncoding },
+	"InvalidFulfillmentComponentData":            func() error { return ErrInvalidFulfillmentComponentData },
+	"InvalidNativeOfferItem":                     func() error { return ErrInvalidNativeOfferItem },
+	"InvalidProof":                               func() error { return ErrInvalidProof },
+	"InvalidSignature":                           func() error { return ErrInvalidSignature },
+	"InvalidSigner":                              func() error { return ErrInvalidSigner },
+	"MissingItemAmount":                          func() error { return ErrMissingItemAmount },
+	"MissingOriginalConsiderationItems":          func() error { return ErrMissingOriginalConsiderationItems },
+	"NoReentrantCalls":                           func() error { return ErrNoReentrantCalls },
+	"NoSpecifiedOrdersAvailable":                 func() error { return ErrNoSpecifiedOrdersAvailable },
+	"OfferAndConsiderationRequiredOnFulfillment": func() error { return ErrOfferAndConsiderationRequiredOnFulfillment },
+	"OfferCriteriaResolverOutOfRange":            func() error { return ErrOfferCriteriaResolverOutOfRange },
+	"PartialFillsNotEnabledForOrder":             func() error { return ErrPartialFillsNotEnabledForOrder },
+	"UnusedItemParameters":                       func() error { return ErrUnusedItemParameters },
+	"BadReturnValueFromERC20OnTransfer":          func() error { return &BadReturnValueFromERC20OnTransferError{} },
+	"BadSignatureV":                              func() error { return &BadSignatureVError{} },
+	"ConsiderationNotMet":                        func() error { return &ConsiderationNotMetError{} },
+	"ERC1155BatchTransferGenericFailure":         func() error { return &ERC1155BatchTransferGenericFailureError{} },
+	"InvalidCallToConduit":                       func() error { return &InvalidCallToConduitError{} },
+	"InvalidConduit":                             func() error { return &InvalidConduitError{} },
+	"InvalidContractOrder":                       func() error { return &InvalidContractOrderError{} },
+	"InvalidERC721TransferAmount":                func() error { return &InvalidERC721TransferAmountError{} },
+	"InvalidMsgValue":                            func() error { return &InvalidMsgValueError{} },
+	"InvalidRestrictedOrder":                     func() error { return &InvalidRestrictedOrderError{} },
+	"InvalidTime":                                func() error { return &InvalidTimeError{} },
+	"MismatchedFulfillmentOfferAndConsiderationComponents": func() error {
+		return &MismatchedFulfillmentOfferAndConsiderationComponentsError{}
+	},
+	"MissingFulfillmentComponentOnAggregation": func() error { return &MissingFulfillmentComponentOnAggregationError{} },
+	"NativeTokenTransferGenericFailure":        func() error { return &NativeTokenTransferGenericFailureError{} },
+	"NoContract":                               func() error { return &NoContractError{} },
+	"OrderAlreadyFilled":                       func() error { return &OrderAlreadyFilledError{} },
+	"OrderCriteriaResolverOutOfRange":          func() error { return &OrderCriteriaResolverOutOfRangeError{} },
+	"OrderIsCancelled":                         func() error { return &OrderIsCancelledError{} },
+	"OrderPartiallyFilled":                     func() error { return &OrderPartiallyFilledError{} },
+	"TokenTransferGenericFailure":              func() error { return &TokenTransferGenericFailureError{} },
+	"UnresolvedConsiderationCriteria":          func() error { return &UnresolvedConsiderationCriteriaError{} },
+	"UnresolvedOfferCriteria":                  func() error { return &UnresolvedOfferCriteriaError{} },
+}
+
+// seaportError returns the typed error of a Seaport revert, nil when name is
+// unknown or the arguments do not fit.
+func seaportError(abiErr abi.Error, args []interface{}) error {
+	newErr, ok := seaportErrors[abiErr.Name]
+	if !ok {
+		return nil
+	}
+	err := newErr()
+	if len(args) > 0 {
+		if copyErr := abiErr.Inputs.Copy(err, args); copyErr != nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// orderUnavailable reports whether err says the order was filled or cancelled
+// by someone else first.
+func orderUnavailable(err error) bool {
+	var cancelled *OrderIsCancelledError
+	var filled *OrderAlreadyFilledError
+	return errors.As(err, &cancelled) || errors.As(err, &filled) || errors.Is(err, ErrNoSpecifiedOrdersAvailable)
+}
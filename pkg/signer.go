@@ -0,0 +1,232 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	wallet "github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/crypto/eip712"
+	"golang.org/x/term"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// Signer signs the orders and transactions of an account. Keys held in
+// process, keystore files and external signers all satisfy it.
+type Signer interface {
+	SignTx(tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error)
+	SignTypedData(typedData *eip712.TypedData) ([]byte, error)
+	EthereumAddress() (common.Address, error)
+}
+
+// NewKeystoreSigner decrypts the geth keystore file at path with passphrase.
+func NewKeystoreSigner(path, passphrase string) (Signer, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("keystore %s: %w", path, err)
+	}
+	return wallet.NewDefaultSigner(key.PrivateKey), nil
+}
+
+// OpenSigner returns the signer w describes. An ExternalSigner has to be
+// closed by the caller.
+func OpenSigner(ctx context.Context, w *WalletConfig) (Signer, error) {
+	address := zeroAddress()
+	if w.Address != "" {
+		address = common.HexToAddress(w.Address)
+	}
+	if w.External != "" {
+		return DialExternalSigner(ctx, w.External, address)
+	}
+
+	var passphrase string
+	if w.PassphraseFile != "" {
+		content, err := os.ReadFile(w.PassphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		passphrase = strings.TrimRight(string(content), "\r\n")
+	} else {
+		var err error
+		if passphrase, err = PromptPassphrase(fmt.Sprintf("passphrase for %s: ", w.Keystore)); err != nil {
+			return nil, err
+		}
+	}
+	signer, err := NewKeystoreSigner(w.Keystore, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if keyAddress, _ := signer.EthereumAddress(); address != zeroAddress() && keyAddress != address {
+		return nil, fmt.Errorf("keystore %s holds %s, not %s", w.Keystore, keyAddress.Hex(), address.Hex())
+	}
+	return signer, nil
+}
+
+// PromptPassphrase reads a passphrase from the terminal without echoing it.
+func PromptPassphrase(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("passphrase prompt needs a terminal")
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(passphrase), nil
+}
+
+// ExternalSigner delegates signing to a Clef compatible signer over JSON-RPC,
+// the key never enters the process. Requests block until the signer answers,
+// which for Clef includes the manual approval.
+type ExternalSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// DialExternalSigner connects to the signer at endpoint, an HTTP URL or an IPC
+// path. When address is the zero address the first account the signer lists
+// is used.
+func DialExternalSigner(ctx context.Context, endpoint string, address common.Address) (*ExternalSigner, error) {
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if address == zeroAddress() {
+		var accounts []common.Address
+		if err := client.CallContext(ctx, &accounts, "account_list"); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("account_list: %w", err)
+		}
+		if len(accounts) == 0 {
+			client.Close()
+			return nil, errors.New("external signer has no account")
+		}
+		address = accounts[0]
+	}
+	return &ExternalSigner{client: client, address: address}, nil
+}
+
+func (s *ExternalSigner) EthereumAddress() (common.Address, error) {
+	return s.address, nil
+}
+
+func (s *ExternalSigner) SignTypedData(typedData *eip712.TypedData) ([]byte, error) {
+	// big integers and byte arrays would reach the signer as JSON floats and
+	// number arrays, which it cannot decode exactly
+	data := *typedData
+	data.Message = messageJSON(typedData.Message).(map[string]interface{})
+	var signature hexutil.Bytes
+	err := s.client.Call(&signature, "account_signTypedData", common.NewMixedcaseAddress(s.address), &data)
+	if err != nil {
+		return nil, fmt.Errorf("account_signTypedData: %w", err)
+	}
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("account_signTypedData: invalid signature length %d", len(signature))
+	}
+	if signature[64] < 27 {
+		signature[64] += 27
+	}
+	return signature, nil
+}
+
+// messageJSON copies an EIP-712 message with integers as decimal strings and
+// bytes as hex strings.
+func messageJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *big.Int:
+		return v.String()
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	case []byte:
+		return hexutil.Encode(v)
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			copied[key] = messageJSON(value)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, value := range v {
+			copied[i] = messageJSON(value)
+		}
+		return copied
+	}
+	return v
+}
+
+func (s *ExternalSigner) SignTx(tx *ethtypes.Transaction, chainID *big.Int) (*ethtypes.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := &apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(s.address),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if to := tx.To(); to != nil {
+		recipient := common.NewMixedcaseAddress(*to)
+		args.To = &recipient
+	}
+	switch tx.Type() {
+	case ethtypes.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case ethtypes.AccessListTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	case ethtypes.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", tx.Type())
+	}
+
+	var result struct {
+		Raw hexutil.Bytes         `json:"raw"`
+		Tx  *ethtypes.Transaction `json:"tx"`
+	}
+	if err := s.client.Call(&result, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("account_signTransaction: %w", err)
+	}
+	signed := result.Tx
+	if len(result.Raw) > 0 {
+		signed = new(ethtypes.Transaction)
+		if err := signed.UnmarshalBinary(result.Raw); err != nil {
+			return nil, fmt.Errorf("account_signTransaction: %w", err)
+		}
+	}
+	if signed == nil {
+		return nil, errors.New("account_signTransaction: no transaction returned")
+	}
+	txSigner := ethtypes.LatestSignerForChainID(chainID)
+	sender, err := ethtypes.Sender(txSigner, signed)
+	if err != nil {
+		return nil, fmt.Errorf("account_signTransaction: %w", err)
+	}
+	// the signer must not have changed what we asked it to sign
+	if sender != s.address || txSigner.Hash(signed) != txSigner.Hash(tx) {
+		return nil, errors.New("account_signTransaction: signed transaction differs from the request")
+	}
+	return signed, nil
+}
+
+func (s *ExternalSigner) Close() {
+	s.client.Close()
+}
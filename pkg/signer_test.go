@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	wallet "github.com/ethersphere/bee/pkg/crypto"
+	"github.com/ethersphere/bee/pkg/crypto/eip712"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenSigner_Keystore(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	dir := t.TempDir()
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	imported, err := ks.ImportECDSA(key, "secret")
+	require.Nil(t, err)
+
+	passphrase := filepath.Join(dir, "passphrase")
+	require.Nil(t, os.WriteFile(passphrase, []byte("secret\n"), 0600))
+	signer, err := OpenSigner(context.Background(), &WalletConfig{Keystore: imported.URL.Path, PassphraseFile: passphrase})
+	require.Nil(t, err)
+	address, err := signer.EthereumAddress()
+	require.Nil(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), address)
+
+	_, err = OpenSigner(context.Background(), &WalletConfig{Keystore: imported.URL.Path, PassphraseFile: passphrase,
+		Address: "0x300b105942d6d181cdfe8199fd48eb09d26efd24"})
+	require.NotNil(t, err)
+
+	_, err = NewKeystoreSigner(imported.URL.Path, "wrong")
+	require.ErrorIs(t, err, keystore.ErrDecrypt)
+}
+
+// standInSigner serves the account_ namespace of Clef with a local key.
+type standInSigner struct {
+	key    *ecdsa.PrivateKey
+	tamper bool
+}
+
+func (s *standInSigner) List() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(s.key.PublicKey)}
+}
+
+func (s *standInSigner) SignTypedData(addr common.MixedcaseAddress, data apitypes.TypedData) (hexutil.Bytes, error) {
+	return wallet.NewDefaultSigner(s.key).SignTypedData(&data)
+}
+
+func (s *standInSigner) SignTransaction(args apitypes.SendTxArgs, methodSelector *string) (map[string]interface{}, error) {
+	if s.tamper {
+		args.Nonce++
+	}
+	tx, err := ethtypes.SignTx(args.ToTransaction(), ethtypes.LatestSignerForChainID(args.ChainID.ToInt()), s.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": tx}, nil
+}
+
+func startStandInSigner(t *testing.T, signer *standInSigner) string {
+	server := rpc.NewServer()
+	require.Nil(t, server.RegisterName("account", signer))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+func TestExternalSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	ctx := context.Background()
+	signer, err := DialExternalSigner(ctx, startStandInSigner(t, &standInSigner{key: key}), zeroAddress())
+	require.Nil(t, err)
+	defer signer.Close()
+
+	address, err := signer.EthereumAddress()
+	require.Nil(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), address)
+
+	amount, _ := new(big.Int).SetString("975000000000000000000", 10)
+	data := &eip712.TypedData{
+		PrimaryType: "OrderComponents",
+		Domain:      eip712.TypedDataDomain{Name: "Seaport", Version: "1.6", ChainId: math.NewHexOrDecimal256(11155111), VerifyingContract: ProtocolAddress},
+	}
+	require.Nil(t, json.Unmarshal([]byte(types), &data.Types))
+	data.Message = (&OrderParameters{
+		Offerer:    address.Hex(),
+		Zone:       zeroAddress().Hex(),
+		ZoneHash:   zero32BytesHexString(),
+		Salt:       "1700000000",
+		ConduitKey: SeaportConduitKey,
+		Consideration: []ConsiderationItem{
+			{Token: zeroAddress().Hex(), StartAmount: amount, EndAmount: amount, Recipient: address.Hex()},
+		},
+		Counter: big.NewInt(3),
+	}).message()
+	signature, err := signer.SignTypedData(data)
+	require.Nil(t, err)
+	expected, err := wallet.NewDefaultSigner(key).SignTypedData(data)
+	require.Nil(t, err)
+	require.Equal(t, expected, signature)
+
+	chainID := big.NewInt(11155111)
+	to := common.HexToAddress(ProtocolAddress)
+	tx := ethtypes.NewTx(&ethtypes.DynamicFeeTx{ChainID: chainID, Nonce: 7, Gas: 90000, GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(10), To: &to, Value: big.NewInt(5), Data: []byte{1, 2, 3}})
+	signed, err := signer.SignTx(tx, chainID)
+	require.Nil(t, err)
+	sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(chainID), signed)
+	require.Nil(t, err)
+	require.Equal(t, address, sender)
+	require.Equal(t, tx.Nonce(), signed.Nonce())
+
+	tampering, err := DialExternalSigner(ctx, startStandInSigner(t, &standInSigner{key: key, tamper: true}), address)
+	require.Nil(t, err)
+	defer tampering.Close()
+	_, err = tampering.SignTx(tx, chainID)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,169 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"opensea-bot/pkg/seaport"
+	"strings"
+)
+
+var ErrSimulationFailed = errors.New("transaction simulation failed")
+
+// RevertError is the decoded revert of a call. Name is the Seaport custom
+// error, Error or Panic; it is empty when the revert data matches no known
+// error. Seaport errors unwrap to their typed error in Err.
+type RevertError struct {
+	Name   string
+	Args   []interface{}
+	Reason string
+	Data   []byte
+	Err    error
+}
+
+func (e *RevertError) Error() string {
+	switch {
+	case e.Err != nil:
+		return "execution reverted: " + e.Err.Error()
+	case e.Reason != "":
+		return "execution reverted: " + e.Reason
+	case e.Name != "":
+		args := make([]string, len(e.Args))
+		for i, arg := range e.Args {
+			args[i] = fmt.Sprint(revertArg(arg))
+		}
+		return fmt.Sprintf("execution reverted: %s(%s)", e.Name, strings.Join(args, ", "))
+	case len(e.Data) > 0:
+		return "execution reverted: " + hexutil.Encode(e.Data)
+	}
+	return "execution reverted"
+}
+
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+func revertArg(arg interface{}) interface{} {
+	switch arg := arg.(type) {
+	case [32]byte:
+		return hexutil.Encode(arg[:])
+	case []byte:
+		return hexutil.Encode(arg)
+	}
+	return arg
+}
+
+// simulate runs tx with eth_call against the pending block and estimates its
+// gas, it returns the transaction to send with that gas limit. Reverts are
+// returned as a RevertError wrapped in ErrSimulationFailed.
+func (a *Account) simulate(ctx context.Context, tx *ethtypes.Transaction) (*ethtypes.Transaction, error) {
+	msg := ethereum.CallMsg{
+		From:  a.WalletAddress(),
+		To:    tx.To(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	if _, err := a.client.PendingCallContract(ctx, msg); err != nil {
+		return nil, simulationError(err)
+	}
+	gas, err := a.client.EstimateGas(ctx, msg)
+	if err != nil {
+		return nil, simulationError(err)
+	}
+	return ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		To:         tx.To(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		Gas:        gas,
+		AccessList: tx.AccessList(),
+	}), nil
+}
+
+// simulationError decodes the revert data an RPC error carries, other errors
+// are returned as they are.
+func simulationError(err error) error {
+	revert := revertOf(err)
+	if revert == nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrSimulationFailed, revert)
+}
+
+// revertOf returns the decoded revert of an RPC error, nil when it carries no
+// revert data.
+func revertOf(err error) *RevertError {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil
+	}
+	encoded, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil
+	}
+	data, decodeErr := hexutil.Decode(encoded)
+	if decodeErr != nil {
+		return nil
+	}
+	return decodeRevert(data)
+}
+
+// minedRevert replays a transaction reverted on-chain with eth_call on the
+// state before its block to recover the revert reason. Transactions before it
+// in the block are not replayed, so the reason may be missing.
+func (a *Account) minedRevert(ctx context.Context, tx *ethtypes.Transaction, receipt *ethtypes.Receipt) error {
+	msg := ethereum.CallMsg{
+		From:  a.WalletAddress(),
+		To:    tx.To(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+		Gas:   tx.Gas(),
+	}
+	block := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	if _, err := a.client.CallContract(ctx, msg, block); err != nil {
+		if revert := revertOf(err); revert != nil {
+			return fmt.Errorf("transaction %s reverted: %w", receipt.TxHash.Hex(), revert)
+		}
+	}
+	return fmt.Errorf("transaction %s reverted", receipt.TxHash.Hex())
+}
+
+// decodeRevert matches data against Error(string), Panic(uint256) and the
+// custom errors of the Seaport ABI.
+func decodeRevert(data []byte) *RevertError {
+	revert := &RevertError{Data: data}
+	if len(data) < 4 {
+		return revert
+	}
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		revert.Name, revert.Reason = "Error", reason
+		if bytes.Equal(data[:4], panicSelector) {
+			revert.Name = "Panic"
+		}
+		return revert
+	}
+	seaportABI, err := seaport.SeaportMetaData.GetAbi()
+	if err != nil {
+		return revert
+	}
+	for name, abiErr := range seaportABI.Errors {
+		if !bytes.Equal(abiErr.ID[:4], data[:4]) {
+			continue
+		}
+		revert.Name = name
+		if args, err := abiErr.Inputs.Unpack(data[4:]); err == nil {
+			revert.Args = args
+			revert.Err = seaportError(abiErr, args)
+		}
+		break
+	}
+	return revert
+}
+
+var panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
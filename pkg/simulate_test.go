@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"opensea-bot/pkg/seaport"
+	"testing"
+)
+
+// revertErr is the error a node returns for a reverted eth_call.
+type revertErr struct {
+	data []byte
+}
+
+func (e *revertErr) Error() string { return "execution reverted" }
+
+func (e *revertErr) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+// revertingBackend reverts every simulated call with data.
+type revertingBackend struct {
+	sendingBackend
+	data []byte
+}
+
+func (b *revertingBackend) PendingCallContract(context.Context, ethereum.CallMsg) ([]byte, error) {
+	return nil, &revertErr{data: b.data}
+}
+
+func revertData(t *testing.T, name string, args ...interface{}) []byte {
+	seaportABI, err := seaport.SeaportMetaData.GetAbi()
+	require.Nil(t, err)
+	abiErr := seaportABI.Errors[name]
+	packed, err := abiErr.Inputs.Pack(args...)
+	require.Nil(t, err)
+	return append(append([]byte{}, abiErr.ID[:4]...), packed...)
+}
+
+func TestDecodeRevert(t *testing.T) {
+	hash := common.HexToHash("0x01")
+	revert := decodeRevert(revertData(t, "OrderIsCancelled", hash))
+	require.Equal(t, "OrderIsCancelled", revert.Name)
+	require.Equal(t, [32]byte(hash), revert.Args[0])
+	require.Equal(t, "execution reverted: seaport: OrderIsCancelled("+hash.Hex()+")", revert.Error())
+
+	revert = decodeRevert(revertData(t, "InsufficientNativeTokensSupplied"))
+	require.Equal(t, "InsufficientNativeTokensSupplied", revert.Name)
+	require.True(t, errors.Is(revert, ErrInsufficientNativeTokensSupplied))
+
+	// Error(string) with "sold out"
+	data := hexutil.MustDecode("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000008" +
+		"736f6c64206f7574000000000000000000000000000000000000000000000000")
+	revert = decodeRevert(data)
+	require.Equal(t, "Error", revert.Name)
+	require.Equal(t, "sold out", revert.Reason)
+
+	revert = decodeRevert([]byte{1, 2, 3, 4})
+	require.Empty(t, revert.Name)
+	require.Equal(t, "execution reverted: 0x01020304", revert.Error())
+}
+
+func TestSendTx_SimulationFails(t *testing.T) {
+	backend := &revertingBackend{data: revertData(t, "InvalidSignature")}
+	account := testAccount(t, backend, nil)
+	nft := &NFT{Identifier: "7", Contract: testContract, TokenStandard: NftType721}
+	_, err := account.TransferNFT(context.Background(), nft, common.HexToAddress(testContract), 1)
+	require.True(t, errors.Is(err, ErrSimulationFailed))
+	var revert *RevertError
+	require.True(t, errors.As(err, &revert))
+	require.Equal(t, "InvalidSignature", revert.Name)
+	require.True(t, errors.Is(err, ErrInvalidSignature))
+	require.Empty(t, backend.sent)
+
+	hash := common.HexToHash("0x02")
+	backend.data = revertData(t, "OrderAlreadyFilled", hash)
+	_, err = account.TransferNFT(context.Background(), nft, common.HexToAddress(testContract), 1)
+	var filled *OrderAlreadyFilledError
+	require.True(t, errors.As(err, &filled))
+	require.Equal(t, hash, filled.OrderHash)
+	require.True(t, orderUnavailable(err))
+}
+
+func TestSeaportErrors(t *testing.T) {
+	seaportABI, err := seaport.SeaportMetaData.GetAbi()
+	require.Nil(t, err)
+	for name := range seaportABI.Errors {
+		require.Contains(t, seaportErrors, name)
+	}
+
+	var notMet *ConsiderationNotMetError
+	err = decodeRevert(revertData(t, "ConsiderationNotMet", big.NewInt(1), big.NewInt(2), big.NewInt(300)))
+	require.True(t, errors.As(err, &notMet))
+	require.Equal(t, int64(1), notMet.OrderIndex.Int64())
+	require.Equal(t, int64(2), notMet.ConsiderationIndex.Int64())
+	require.Equal(t, int64(300), notMet.ShortfallAmount.Int64())
+
+	var badV *BadSignatureVError
+	err = decodeRevert(revertData(t, "BadSignatureV", uint8(29)))
+	require.True(t, errors.As(err, &badV))
+	require.Equal(t, uint8(29), badV.V)
+
+	var batch *ERC1155BatchTransferGenericFailureError
+	token := common.HexToAddress(testContract)
+	err = decodeRevert(revertData(t, "ERC1155BatchTransferGenericFailure", token, token, token,
+		[]*big.Int{big.NewInt(7)}, []*big.Int{big.NewInt(2)}))
+	require.True(t, errors.As(err, &batch))
+	require.Equal(t, token, batch.Token)
+	require.Equal(t, int64(7), batch.Identifiers[0].Int64())
+
+	var conduit *InvalidConduitError
+	err = decodeRevert(revertData(t, "InvalidConduit", [32]byte{1}, token))
+	require.True(t, errors.As(err, &conduit))
+	require.Equal(t, common.Hash{1}, conduit.ConduitKey)
+	require.Equal(t, token, conduit.Conduit)
+}
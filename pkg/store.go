@@ -0,0 +1,188 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"strings"
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderCreated   OrderStatus = "created"
+	OrderActive    OrderStatus = "active"
+	OrderFilled    OrderStatus = "filled"
+	OrderCancelled OrderStatus = "cancelled"
+	OrderExpired   OrderStatus = "expired"
+	// OrderRejected is an order OpenSea refused when it was posted.
+	OrderRejected OrderStatus = "rejected"
+)
+
+const (
+	SideListing         = "listing"
+	SideOffer           = "offer"
+	SideCollectionOffer = "collection_offer"
+	SideTraitOffer      = "trait_offer"
+	SideBundle          = "bundle"
+)
+
+var ErrOrderNotFound = errors.New("order not found")
+
+var orderKeyPrefix = []byte("order/")
+
+var txKeyPrefix = []byte("tx/")
+
+type StoredOrder struct {
+	OrderHash  string          `json:"order_hash"`
+	Chain      string          `json:"chain"`
+	Side       string          `json:"side"`
+	Parameters OrderParameters `json:"parameters"`
+	Signature  string          `json:"signature"`
+	Price      string          `json:"price"`
+	ExpireAt   int64           `json:"expire_at"`
+	Status     OrderStatus     `json:"status"`
+	CreatedAt  int64           `json:"created_at"`
+	UpdatedAt  int64           `json:"updated_at"`
+}
+
+// Open reports whether the order may still be fulfilled as far as the store
+// knows.
+func (o *StoredOrder) Open() bool {
+	return o.Status == OrderCreated || o.Status == OrderActive
+}
+
+// OrderStore persists the orders signed by the bot in a leveldb database keyed
+// by order hash.
+type OrderStore struct {
+	db *leveldb.DB
+}
+
+func OpenOrderStore(path string) (*OrderStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderStore{db: db}, nil
+}
+
+func (s *OrderStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *OrderStore) Put(order *StoredOrder) error {
+	if order.OrderHash == "" {
+		return errors.New("order hash is empty")
+	}
+	now := time.Now().Unix()
+	if order.CreatedAt == 0 {
+		order.CreatedAt = now
+	}
+	order.UpdatedAt = now
+
+	value, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(orderKey(order.OrderHash), value, nil)
+}
+
+func (s *OrderStore) Get(orderHash string) (*StoredOrder, error) {
+	value, err := s.db.Get(orderKey(orderHash), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var order StoredOrder
+	if err := json.Unmarshal(value, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// List returns the stored orders in one of statuses, or every order when no
+// status is given.
+func (s *OrderStore) List(statuses ...OrderStatus) ([]*StoredOrder, error) {
+	iter := s.db.NewIterator(util.BytesPrefix(orderKeyPrefix), nil)
+	defer iter.Release()
+
+	orders := make([]*StoredOrder, 0)
+	for iter.Next() {
+		var order StoredOrder
+		if err := json.Unmarshal(iter.Value(), &order); err != nil {
+			return nil, err
+		}
+		if len(statuses) > 0 && !hasStatus(statuses, order.Status) {
+			continue
+		}
+		orders = append(orders, &order)
+	}
+	return orders, iter.Error()
+}
+
+func (s *OrderStore) UpdateStatus(orderHash string, status OrderStatus) error {
+	order, err := s.Get(orderHash)
+	if err != nil {
+		return err
+	}
+	if order.Status == status {
+		return nil
+	}
+	order.Status = status
+	return s.Put(order)
+}
+
+func hasStatus(statuses []OrderStatus, status OrderStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func orderKey(orderHash string) []byte {
+	return append(append([]byte{}, orderKeyPrefix...), strings.ToLower(orderHash)...)
+}
+
+// PutPendingTx journals a transaction sent by a TxManager.
+func (s *OrderStore) PutPendingTx(p *PendingTx) error {
+	value, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(txKey(p.ChainID, p.From, p.Nonce), value, nil)
+}
+
+func (s *OrderStore) DeletePendingTx(p *PendingTx) error {
+	return s.db.Delete(txKey(p.ChainID, p.From, p.Nonce), nil)
+}
+
+// PendingTxs returns the journaled transactions of wallet on chainID by nonce.
+func (s *OrderStore) PendingTxs(chainID int64, wallet common.Address) ([]*PendingTx, error) {
+	prefix := []byte(fmt.Sprintf("%s%d/%s/", txKeyPrefix, chainID, strings.ToLower(wallet.Hex())))
+	iter := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	pending := make([]*PendingTx, 0)
+	for iter.Next() {
+		var p PendingTx
+		if err := json.Unmarshal(iter.Value(), &p); err != nil {
+			return nil, err
+		}
+		pending = append(pending, &p)
+	}
+	return pending, iter.Error()
+}
+
+// txKey pads the nonce so that keys sort by nonce.
+func txKey(chainID int64, wallet common.Address, nonce uint64) []byte {
+	return []byte(fmt.Sprintf("%s%d/%s/%020d", txKeyPrefix, chainID, strings.ToLower(wallet.Hex()), nonce))
+}
@@ -0,0 +1,39 @@
+package pkg
+
+import (
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestOrderStore(t *testing.T) {
+	store, err := OpenOrderStore(filepath.Join(t.TempDir(), "orders.db"))
+	require.Nil(t, err)
+	defer store.Close()
+
+	require.NotNil(t, store.Put(&StoredOrder{}))
+
+	listing := &StoredOrder{OrderHash: "0xAB", Side: SideListing, Price: "0.3", Status: OrderCreated}
+	offer := &StoredOrder{OrderHash: "0xcd", Side: SideOffer, Price: "0.1", Status: OrderActive}
+	require.Nil(t, store.Put(listing))
+	require.Nil(t, store.Put(offer))
+	require.NotZero(t, listing.CreatedAt)
+
+	got, err := store.Get("0xab")
+	require.Nil(t, err)
+	require.Equal(t, "0.3", got.Price)
+	require.True(t, got.Open())
+
+	_, err = store.Get("0xef")
+	require.ErrorIs(t, err, ErrOrderNotFound)
+
+	require.Nil(t, store.UpdateStatus("0xab", OrderFilled))
+	open, err := store.List(OrderCreated, OrderActive)
+	require.Nil(t, err)
+	require.Len(t, open, 1)
+	require.Equal(t, "0xcd", open[0].OrderHash)
+
+	all, err := store.List()
+	require.Nil(t, err)
+	require.Len(t, all, 2)
+}
@@ -0,0 +1,175 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"opensea-bot/pkg/seaport"
+)
+
+// ErrMixedSeaport is returned when the listings of a sweep are signed for
+// different Seaport versions.
+var ErrMixedSeaport = errors.New("sweep spans several seaport versions")
+
+type SweepResult struct {
+	Receipt  *ethtypes.Receipt
+	Total    decimal.Decimal
+	Filled   []string
+	Unfilled []string
+}
+
+// Sweep buys up to limit of the cheapest listings of the collection priced at
+// or below maxPrice in a single FulfillAvailableAdvancedOrders transaction. An
+// empty budget places no cap on the total spend.
+func (a *Account) Sweep(ctx context.Context, limit int, maxPrice, budget string) (*SweepResult, error) {
+	unitPrice, err := parsePositive(maxPrice)
+	if err != nil {
+		return nil, fmt.Errorf("max price: %w", err)
+	}
+	totalBudget := decimal.Zero
+	if budget != "" {
+		if totalBudget, err = parsePositive(budget); err != nil {
+			return nil, fmt.Errorf("budget: %w", err)
+		}
+	}
+
+	listings, err := a.GetBestListing(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	selected, total := selectListings(listings, unitPrice, totalBudget)
+	if len(selected) == 0 {
+		return nil, errors.New("no listing matches the sweep criteria")
+	}
+
+	var address common.Address
+	hashes := make([]string, 0, len(selected))
+	orders := make([]seaport.AdvancedOrder, 0, len(selected))
+	for i := range selected {
+		fulfillment, err := a.listingFulfillmentData(ctx, &selected[i])
+		if err != nil {
+			return nil, err
+		}
+		if len(fulfillment.FulfillmentData.Orders) == 0 {
+			return nil, fmt.Errorf("no fulfillment order returned for %s", selected[i].OrderHash)
+		}
+		order, err := fulfillment.advancedOrder(0)
+		if err != nil {
+			return nil, err
+		}
+		// one transaction calls a single Seaport contract
+		target, err := fulfillment.seaportAddress(selected[i].ProtocolAddress)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && target != address {
+			return nil, fmt.Errorf("%w: %s is on Seaport %s, %s on Seaport %s", ErrMixedSeaport,
+				selected[i].OrderHash, seaportVersions[target], hashes[0], seaportVersions[address])
+		}
+		address = target
+		orders = append(orders, *order)
+		hashes = append(hashes, selected[i].OrderHash)
+	}
+
+	value := big.NewInt(0)
+	for _, order := range orders {
+		value.Add(value, nativeAmount(order.Parameters.Consideration))
+	}
+	offerFulfillments, considerationFulfillments := aggregateFulfillments(orders)
+
+	instance, err := seaport.NewSeaport(address, a.client)
+	if err != nil {
+		return nil, err
+	}
+	opts := a.transactOpts(ctx, value)
+	tx, err := instance.FulfillAvailableAdvancedOrders(opts, orders, []seaport.CriteriaResolver{},
+		offerFulfillments, considerationFulfillments, hexStringToByte32(a.chain.ConduitKey),
+		a.WalletAddress(), big.NewInt(int64(len(orders))))
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := a.sendTx(ctx, fmt.Sprintf("sweep %d orders", len(orders)), tx)
+	if err != nil {
+		return nil, err
+	}
+
+	filled := fulfilledOrders(instance, address, receipt)
+	result := &SweepResult{Receipt: receipt, Total: total}
+	for _, hash := range hashes {
+		if filled[common.HexToHash(hash)] {
+			result.Filled = append(result.Filled, hash)
+		} else {
+			result.Unfilled = append(result.Unfilled, hash)
+		}
+	}
+	return result, nil
+}
+
+// fulfilledOrders collects the order hashes of every OrderFulfilled event the
+// Seaport contract at address emitted in receipt.
+func fulfilledOrders(instance *seaport.Seaport, address common.Address, receipt *ethtypes.Receipt) map[common.Hash]bool {
+	filled := make(map[common.Hash]bool)
+	for _, l := range receipt.Logs {
+		if l.Address != address {
+			continue
+		}
+		event, err := instance.ParseOrderFulfilled(*l)
+		if err != nil {
+			continue
+		}
+		filled[common.Hash(event.OrderHash)] = true
+	}
+	return filled
+}
+
+// aggregateFulfillments groups the offer items of all orders by offerer,
+// conduit and token, and the consideration items by token and recipient, so
+// that Seaport performs one transfer per group.
+func aggregateFulfillments(orders []seaport.AdvancedOrder) ([][]seaport.FulfillmentComponent, [][]seaport.FulfillmentComponent) {
+	offer := newFulfillmentGroups()
+	consideration := newFulfillmentGroups()
+	for i, order := range orders {
+		params := order.Parameters
+		for j, item := range params.Offer {
+			key := fmt.Sprintf("%d:%s:%s:%s:%s", item.ItemType, item.Token.Hex(), item.IdentifierOrCriteria,
+				params.Offerer.Hex(), hexutil.Encode(params.ConduitKey[:]))
+			offer.add(key, i, j)
+		}
+		for j, item := range params.Consideration {
+			key := fmt.Sprintf("%d:%s:%s:%s", item.ItemType, item.Token.Hex(), item.IdentifierOrCriteria,
+				item.Recipient.Hex())
+			consideration.add(key, i, j)
+		}
+	}
+	return offer.components, consideration.components
+}
+
+type fulfillmentGroups struct {
+	index      map[string]int
+	components [][]seaport.FulfillmentComponent
+}
+
+func newFulfillmentGroups() *fulfillmentGroups {
+	return &fulfillmentGroups{
+		index:      make(map[string]int),
+		components: make([][]seaport.FulfillmentComponent, 0),
+	}
+}
+
+func (g *fulfillmentGroups) add(key string, orderIndex, itemIndex int) {
+	component := seaport.FulfillmentComponent{
+		OrderIndex: big.NewInt(int64(orderIndex)),
+		ItemIndex:  big.NewInt(int64(itemIndex)),
+	}
+	if i, ok := g.index[key]; ok {
+		g.components[i] = append(g.components[i], component)
+		return
+	}
+	g.index[key] = len(g.components)
+	g.components = append(g.components, []seaport.FulfillmentComponent{component})
+}
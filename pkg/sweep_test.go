@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"io"
+	"math/big"
+	"net/http"
+	"opensea-bot/pkg/seaport"
+	"strings"
+	"testing"
+)
+
+func TestAggregateFulfillments(t *testing.T) {
+	nft := common.HexToAddress("0x300b105942d6d181cdfe8199fd48eb09d26efd24")
+	feeRecipient := common.HexToAddress("0x0000a26b00c1f0df003000390027140000faa719")
+	order := func(offerer string, identifier int64) seaport.AdvancedOrder {
+		seller := common.HexToAddress(offerer)
+		return seaport.AdvancedOrder{Parameters: seaport.OrderParameters{
+			Offerer: seller,
+			Offer: []seaport.OfferItem{
+				{ItemType: 2, Token: nft, IdentifierOrCriteria: big.NewInt(identifier)},
+			},
+			Consideration: []seaport.ConsiderationItem{
+				{ItemType: 0, IdentifierOrCriteria: big.NewInt(0), Recipient: seller},
+				{ItemType: 0, IdentifierOrCriteria: big.NewInt(0), Recipient: feeRecipient},
+			},
+		}}
+	}
+
+	offer, consideration := aggregateFulfillments([]seaport.AdvancedOrder{
+		order("0x01", 1),
+		order("0x02", 2),
+		order("0x01", 3),
+	})
+
+	require.Len(t, offer, 3)
+	// seller 0x01 proceeds, shared fee recipient, seller 0x02 proceeds
+	require.Len(t, consideration, 3)
+	require.Len(t, consideration[0], 2)
+	require.Equal(t, int64(2), consideration[0][1].OrderIndex.Int64())
+	require.Len(t, consideration[1], 3)
+	require.Equal(t, int64(1), consideration[1][0].ItemIndex.Int64())
+	require.Len(t, consideration[2], 1)
+}
+
+func TestSweep_SeaportVersions(t *testing.T) {
+	// protocol addresses by order hash
+	protocols := map[string]string{"0x01": SeaportV16Address, "0x02": SeaportV16Address}
+	client := apiClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			listings := make([]string, 0)
+			for _, hash := range []string{"0x01", "0x02"} {
+				listings = append(listings, fmt.Sprintf(`{"order_hash": %q, "protocol_address": %q,
+					"price": {"current": {"currency": "ETH", "decimals": 18, "value": "1000000000000000000"}}}`, hash, protocols[hash]))
+			}
+			_, _ = w.Write([]byte(`{"listings": [` + strings.Join(listings, ",") + `]}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		to := protocols["0x01"]
+		if strings.Contains(string(body), `"0x02"`) {
+			to = protocols["0x02"]
+		}
+		_, _ = w.Write([]byte(strings.Replace(fulfillmentFixture, SeaportV16Address, to, 1)))
+	})
+	backend := &sendingBackend{}
+	account := testAccount(t, backend, client)
+
+	_, err := account.Sweep(context.Background(), 2, "1", "")
+	require.Nil(t, err)
+	require.Len(t, backend.sent, 1)
+	require.Equal(t, common.HexToAddress(SeaportV16Address), *backend.sent[0].To())
+
+	protocols["0x02"] = ProtocolAddress
+	_, err = account.Sweep(context.Background(), 2, "1", "")
+	require.True(t, errors.Is(err, ErrMixedSeaport))
+	require.Len(t, backend.sent, 1)
+}
@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"opensea-bot/pkg/erc1155"
+	"opensea-bot/pkg/erc721"
+)
+
+// TransferNFT moves amount units of nft from the wallet to to with
+// safeTransferFrom, amount is ignored for ERC-721 tokens.
+func (a *Account) TransferNFT(ctx context.Context, nft *NFT, to common.Address, amount int64) (*ethtypes.Receipt, error) {
+	if to == zeroAddress() {
+		return nil, errors.New("transfer to the zero address")
+	}
+	id, err := nft.identifier()
+	if err != nil {
+		return nil, err
+	}
+	contract := common.HexToAddress(nft.Contract)
+
+	var tx *ethtypes.Transaction
+	switch nft.nftType() {
+	case 2:
+		token, err := erc721.NewERC721Transactor(contract, a.client)
+		if err != nil {
+			return nil, err
+		}
+		tx, err = token.SafeTransferFrom(a.transactOpts(ctx, nil), a.WalletAddress(), to, id)
+		if err != nil {
+			return nil, err
+		}
+	case 3:
+		if amount <= 0 {
+			return nil, fmt.Errorf("invalid amount %d", amount)
+		}
+		token, err := erc1155.NewERC1155Transactor(contract, a.client)
+		if err != nil {
+			return nil, err
+		}
+		tx, err = token.SafeTransferFrom(a.transactOpts(ctx, nil), a.WalletAddress(), to, id, big.NewInt(amount), []byte{})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported token standard %q", nft.TokenStandard)
+	}
+	return a.sendTx(ctx, fmt.Sprintf("transfer %s #%s to %s", nft.Contract, nft.Identifier, to.Hex()), tx)
+}
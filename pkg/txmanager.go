@@ -0,0 +1,512 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrTxReplaced = errors.New("transaction replaced by another transaction of the wallet")
+
+var ErrFeeCapReached = errors.New("fee cap reached, transaction cannot be replaced")
+
+// TxConfig tunes how a TxManager prices and follows transactions.
+type TxConfig struct {
+	// MaxFeePerGas and MaxTipCap cap the fees paid per gas, in wei. Nil means
+	// no cap.
+	MaxFeePerGas *big.Int
+	MaxTipCap    *big.Int
+	// Confirmations is the number of blocks, including the one a transaction
+	// is mined in, Wait waits for.
+	Confirmations uint64
+	// A transaction not mined StuckAfter its last broadcast is sped up by
+	// BumpPercent, at most MaxBumps times.
+	StuckAfter   time.Duration
+	BumpPercent  int64
+	MaxBumps     int
+	PollInterval time.Duration
+}
+
+func DefaultTxConfig() TxConfig {
+	return TxConfig{
+		Confirmations: 1,
+		StuckAfter:    3 * time.Minute,
+		BumpPercent:   20,
+		MaxBumps:      3,
+		PollInterval:  2 * time.Second,
+	}
+}
+
+// PendingTx is a transaction sent by a TxManager and not yet confirmed. Txs
+// holds every broadcast version of it, the original first, all sharing Nonce.
+type PendingTx struct {
+	Label   string                  `json:"label"`
+	From    common.Address          `json:"from"`
+	ChainID int64                   `json:"chain_id"`
+	Nonce   uint64                  `json:"nonce"`
+	Txs     []*ethtypes.Transaction `json:"txs"`
+	SentAt  time.Time               `json:"sent_at"`
+	// Cancelled is set once the transaction was replaced by a cancellation.
+	Cancelled bool `json:"cancelled"`
+}
+
+func (p *PendingTx) latest() *ethtypes.Transaction {
+	return p.Txs[len(p.Txs)-1]
+}
+
+// TxManager sends the transactions of one wallet. It hands out nonces locally
+// so that transactions can be sent back to back, prices them with EIP-1559
+// fees, replaces stuck ones and records pending ones in a journal when the
+// account has an order store. Accounts of the same wallet must share it.
+type TxManager struct {
+	backend Backend
+	signer  Signer
+	from    common.Address
+	chainID *big.Int
+	config  TxConfig
+
+	mu      sync.Mutex
+	nonce   *uint64
+	pending map[uint64]*PendingTx
+	journal *OrderStore
+}
+
+func NewTxManager(backend Backend, signer Signer, chainID *big.Int, config TxConfig) (*TxManager, error) {
+	from, err := signer.EthereumAddress()
+	if err != nil {
+		return nil, err
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultTxConfig().PollInterval
+	}
+	if config.Confirmations == 0 {
+		config.Confirmations = 1
+	}
+	return &TxManager{
+		backend: backend,
+		signer:  signer,
+		from:    from,
+		chainID: chainID,
+		config:  config,
+		pending: make(map[uint64]*PendingTx),
+	}, nil
+}
+
+// SetJournal records pending transactions in store so that Recover finds them
+// after a restart.
+func (m *TxManager) SetJournal(store *OrderStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.journal = store
+}
+
+// Send assigns the next nonce and current fees to tx, signs and broadcasts it.
+// Only the recipient, value, data, gas limit and access list of tx are kept.
+func (m *TxManager) Send(ctx context.Context, label string, tx *ethtypes.Transaction) (*PendingTx, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nonce, err := m.nextNonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tip, feeCap, err := m.fees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := m.signer.SignTx(m.build(tx, nonce, tip, feeCap), m.chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &PendingTx{
+		Label:   label,
+		From:    m.from,
+		ChainID: m.chainID.Int64(),
+		Nonce:   nonce,
+		Txs:     []*ethtypes.Transaction{signed},
+		SentAt:  time.Now(),
+	}
+	// journaled first so that a crash right after the broadcast loses nothing
+	m.record(p)
+	if err := m.backend.SendTransaction(ctx, signed); err != nil {
+		m.forget(p)
+		// resync with the node on the next send
+		m.nonce = nil
+		return nil, err
+	}
+	m.pending[nonce] = p
+	log.Println(label, "tx", signed.Hash().Hex(), "nonce", nonce)
+	return p, nil
+}
+
+// Wait blocks until p is mined and has the configured confirmations, speeding
+// it up while it is stuck. The receipt is returned whatever its status.
+func (m *TxManager) Wait(ctx context.Context, p *PendingTx) (*ethtypes.Receipt, error) {
+	ticker := time.NewTicker(m.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := m.checkPending(ctx, p)
+		if err != nil || receipt != nil {
+			return receipt, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkPending returns the receipt of p once it is confirmed, nil while it is
+// still pending.
+func (m *TxManager) checkPending(ctx context.Context, p *PendingTx) (*ethtypes.Receipt, error) {
+	// read before the receipts so that a nonce past p without one of its
+	// receipts means another transaction took the nonce
+	mined, err := m.backend.NonceAt(ctx, m.from, nil)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := m.receipt(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if receipt == nil {
+		if mined > p.Nonce {
+			m.done(p)
+			return nil, fmt.Errorf("%w: nonce %d", ErrTxReplaced, p.Nonce)
+		}
+		if m.config.StuckAfter > 0 && time.Since(m.sentAt(p)) >= m.config.StuckAfter && m.bumps(p) < m.config.MaxBumps {
+			if err := m.SpeedUp(ctx, p); err != nil {
+				log.Printf("speed up %s nonce %d: %v", p.Label, p.Nonce, err)
+			}
+		}
+		return nil, nil
+	}
+
+	head, err := m.backend.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head+1 < receipt.BlockNumber.Uint64()+m.config.Confirmations {
+		return nil, nil
+	}
+	m.done(p)
+	// the original may still be mined before its cancellation
+	if p.Cancelled && m.isCancellation(p, receipt.TxHash) {
+		return receipt, fmt.Errorf("%s nonce %d was cancelled", p.Label, p.Nonce)
+	}
+	return receipt, nil
+}
+
+// isCancellation reports whether hash is a version of p sent by Cancel, an
+// empty transfer to the wallet itself.
+func (m *TxManager) isCancellation(p *PendingTx, hash common.Hash) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, tx := range p.Txs {
+		if tx.Hash() == hash {
+			return tx.To() != nil && *tx.To() == m.from && tx.Value().Sign() == 0 && len(tx.Data()) == 0
+		}
+	}
+	return false
+}
+
+// receipt looks for a receipt of any version of p, newest first.
+func (m *TxManager) receipt(ctx context.Context, p *PendingTx) (*ethtypes.Receipt, error) {
+	m.mu.Lock()
+	txs := append([]*ethtypes.Transaction{}, p.Txs...)
+	m.mu.Unlock()
+	for i := len(txs) - 1; i >= 0; i-- {
+		receipt, err := m.backend.TransactionReceipt(ctx, txs[i].Hash())
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return receipt, nil
+	}
+	return nil, nil
+}
+
+// SpeedUp rebroadcasts p with fees raised by BumpPercent, or to the current
+// market fees when these are higher.
+func (m *TxManager) SpeedUp(ctx context.Context, p *PendingTx) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.replace(ctx, p, p.latest())
+}
+
+// Cancel replaces p by an empty transfer to the wallet itself.
+func (m *TxManager) Cancel(ctx context.Context, p *PendingTx) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cancel := ethtypes.NewTx(&ethtypes.DynamicFeeTx{To: &m.from, Gas: 21000, Value: new(big.Int)})
+	if err := m.replace(ctx, p, cancel); err != nil {
+		return err
+	}
+	p.Cancelled = true
+	m.record(p)
+	return nil
+}
+
+func (m *TxManager) replace(ctx context.Context, p *PendingTx, tx *ethtypes.Transaction) error {
+	previous := p.latest()
+	tip, feeCap, err := m.fees(ctx)
+	if err != nil {
+		return err
+	}
+	if feeCap, err = m.bump(previous.GasFeeCap(), feeCap, m.config.MaxFeePerGas); err != nil {
+		return err
+	}
+	if tip != nil {
+		if tip, err = m.bump(previous.GasTipCap(), tip, m.config.MaxTipCap); err != nil {
+			return err
+		}
+		if tip.Cmp(feeCap) > 0 {
+			tip = feeCap
+		}
+	}
+
+	signed, err := m.signer.SignTx(m.build(tx, p.Nonce, tip, feeCap), m.chainID)
+	if err != nil {
+		return err
+	}
+	if err := m.backend.SendTransaction(ctx, signed); err != nil {
+		return err
+	}
+	p.Txs = append(p.Txs, signed)
+	p.SentAt = time.Now()
+	m.record(p)
+	log.Println("replace", p.Label, "tx", previous.Hash().Hex(), "by", signed.Hash().Hex(), "fee cap", feeCap)
+	return nil
+}
+
+// bump raises previous by BumpPercent, at least to market, within limit.
+// Nodes only accept a replacement paying at least 10% more.
+func (m *TxManager) bump(previous, market, limit *big.Int) (*big.Int, error) {
+	percent := m.config.BumpPercent
+	if percent < 10 {
+		percent = 10
+	}
+	bumped := new(big.Int).Mul(previous, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	bumped.Quo(bumped, big.NewInt(100))
+	if market != nil && market.Cmp(bumped) > 0 {
+		bumped = new(big.Int).Set(market)
+	}
+	if limit != nil && bumped.Cmp(limit) > 0 {
+		minimum := new(big.Int).Mul(previous, big.NewInt(110))
+		minimum.Add(minimum, big.NewInt(99))
+		minimum.Quo(minimum, big.NewInt(100))
+		if minimum.Cmp(limit) > 0 {
+			return nil, ErrFeeCapReached
+		}
+		bumped = new(big.Int).Set(limit)
+	}
+	return bumped, nil
+}
+
+// Pending returns the transactions sent and not yet confirmed, by nonce.
+func (m *TxManager) Pending() []*PendingTx {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pending := make([]*PendingTx, 0, len(m.pending))
+	for _, p := range m.pending {
+		pending = append(pending, p)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Nonce < pending[j].Nonce })
+	return pending
+}
+
+// Recover loads the journaled transactions of the wallet, drops the ones
+// mined or replaced meanwhile and rebroadcasts the others, which it returns
+// for the caller to Wait on.
+func (m *TxManager) Recover(ctx context.Context) ([]*PendingTx, error) {
+	m.mu.Lock()
+	journal := m.journal
+	m.mu.Unlock()
+	if journal == nil {
+		return nil, nil
+	}
+	journaled, err := journal.PendingTxs(m.chainID.Int64(), m.from)
+	if err != nil {
+		return nil, err
+	}
+	mined, err := m.backend.NonceAt(ctx, m.from, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	recovered := make([]*PendingTx, 0, len(journaled))
+	for _, p := range journaled {
+		receipt, err := m.receipt(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil || mined > p.Nonce {
+			m.forget(p)
+			continue
+		}
+		if err := m.backend.SendTransaction(ctx, p.latest()); err != nil && !alreadyKnown(err) {
+			log.Printf("rebroadcast %s nonce %d: %v", p.Label, p.Nonce, err)
+		}
+		m.mu.Lock()
+		m.pending[p.Nonce] = p
+		if m.nonce == nil || *m.nonce <= p.Nonce {
+			next := p.Nonce + 1
+			m.nonce = &next
+		}
+		m.mu.Unlock()
+		recovered = append(recovered, p)
+	}
+	return recovered, nil
+}
+
+func (m *TxManager) nextNonce(ctx context.Context) (uint64, error) {
+	// the node may know transactions sent around the manager
+	nonce, err := m.backend.PendingNonceAt(ctx, m.from)
+	if err != nil {
+		return 0, err
+	}
+	if m.nonce != nil && *m.nonce > nonce {
+		nonce = *m.nonce
+	}
+	next := nonce + 1
+	m.nonce = &next
+	return nonce, nil
+}
+
+// fees returns the tip and fee cap of a new transaction, a nil tip and the gas
+// price on chains without EIP-1559.
+func (m *TxManager) fees(ctx context.Context) (*big.Int, *big.Int, error) {
+	head, err := m.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if head.BaseFee == nil {
+		price, err := m.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, capped(price, m.config.MaxFeePerGas), nil
+	}
+
+	tip, err := m.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	tip = capped(tip, m.config.MaxTipCap)
+	feeCap := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip)
+	feeCap = capped(feeCap, m.config.MaxFeePerGas)
+	if tip.Cmp(feeCap) > 0 {
+		tip = feeCap
+	}
+	return tip, feeCap, nil
+}
+
+func (m *TxManager) build(tx *ethtypes.Transaction, nonce uint64, tip, feeCap *big.Int) *ethtypes.Transaction {
+	if tip == nil {
+		return ethtypes.NewTx(&ethtypes.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: feeCap,
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		})
+	}
+	return ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		ChainID:    m.chainID,
+		Nonce:      nonce,
+		GasTipCap:  tip,
+		GasFeeCap:  feeCap,
+		Gas:        tx.Gas(),
+		To:         tx.To(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	})
+}
+
+func (m *TxManager) sentAt(p *PendingTx) time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return p.SentAt
+}
+
+func (m *TxManager) bumps(p *PendingTx) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(p.Txs) - 1
+}
+
+func (m *TxManager) done(p *PendingTx) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.pending, p.Nonce)
+	m.forget(p)
+}
+
+func (m *TxManager) record(p *PendingTx) {
+	if m.journal == nil {
+		return
+	}
+	if err := m.journal.PutPendingTx(p); err != nil {
+		log.Printf("journal %s nonce %d: %v", p.Label, p.Nonce, err)
+	}
+}
+
+func (m *TxManager) forget(p *PendingTx) {
+	if m.journal == nil {
+		return
+	}
+	if err := m.journal.DeletePendingTx(p); err != nil {
+		log.Printf("journal %s nonce %d: %v", p.Label, p.Nonce, err)
+	}
+}
+
+func capped(value, limit *big.Int) *big.Int {
+	if limit != nil && value.Cmp(limit) > 0 {
+		return new(big.Int).Set(limit)
+	}
+	return value
+}
+
+func alreadyKnown(err error) bool {
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "already known") || strings.Contains(message, "nonce too low")
+}
+
+// SetTxManager makes the account send its transactions through m, which lets
+// accounts of the same wallet share nonces.
+func (a *Account) SetTxManager(m *TxManager) {
+	a.txs = m
+}
+
+// TxManager returns the transaction manager of the account, creating one with
+// the default configuration when none was set.
+func (a *Account) TxManager() (*TxManager, error) {
+	if a.txs == nil {
+		m, err := NewTxManager(a.client, a.signer, a.chainID, DefaultTxConfig())
+		if err != nil {
+			return nil, err
+		}
+		if a.store != nil {
+			m.SetJournal(a.store)
+		}
+		a.txs = m
+	}
+	return a.txs, nil
+}
@@ -0,0 +1,273 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	wallet "github.com/ethersphere/bee/pkg/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// poolBackend mines a transaction as soon as it pays minFeeCap and keeps the
+// others in its pool. Every BlockNumber call produces a block.
+type poolBackend struct {
+	Backend
+	mu        sync.Mutex
+	minFeeCap *big.Int
+	nonce     uint64
+	head      uint64
+	pool      map[uint64]*ethtypes.Transaction
+	sent      []*ethtypes.Transaction
+	receipts  map[common.Hash]*ethtypes.Receipt
+}
+
+func newPoolBackend() *poolBackend {
+	return &poolBackend{head: 1, pool: map[uint64]*ethtypes.Transaction{}, receipts: map[common.Hash]*ethtypes.Receipt{}}
+}
+
+func (b *poolBackend) HeaderByNumber(context.Context, *big.Int) (*ethtypes.Header, error) {
+	return &ethtypes.Header{Number: big.NewInt(1), BaseFee: big.NewInt(10)}, nil
+}
+
+func (b *poolBackend) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return big.NewInt(2), nil
+}
+
+func (b *poolBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	nonce := b.nonce
+	for n := range b.pool {
+		if n >= nonce {
+			nonce = n + 1
+		}
+	}
+	return nonce, nil
+}
+
+func (b *poolBackend) NonceAt(context.Context, common.Address, *big.Int) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nonce, nil
+}
+
+func (b *poolBackend) BlockNumber(context.Context) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.head++
+	return b.head, nil
+}
+
+func (b *poolBackend) SendTransaction(_ context.Context, tx *ethtypes.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if tx.Nonce() < b.nonce {
+		return errors.New("nonce too low")
+	}
+	b.sent = append(b.sent, tx)
+	if b.minFeeCap != nil && tx.GasFeeCap().Cmp(b.minFeeCap) < 0 {
+		b.pool[tx.Nonce()] = tx
+		return nil
+	}
+	delete(b.pool, tx.Nonce())
+	b.receipts[tx.Hash()] = &ethtypes.Receipt{TxHash: tx.Hash(), Status: ethtypes.ReceiptStatusSuccessful,
+		BlockNumber: new(big.Int).SetUint64(b.head)}
+	b.nonce = tx.Nonce() + 1
+	return nil
+}
+
+func (b *poolBackend) TransactionReceipt(_ context.Context, txHash common.Hash) (*ethtypes.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	receipt, ok := b.receipts[txHash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+func testTxManager(t *testing.T, backend Backend, config TxConfig) *TxManager {
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	m, err := NewTxManager(backend, wallet.NewDefaultSigner(key), big.NewInt(11155111), config)
+	require.Nil(t, err)
+	return m
+}
+
+func draftTx() *ethtypes.Transaction {
+	to := common.HexToAddress(testContract)
+	return ethtypes.NewTx(&ethtypes.DynamicFeeTx{To: &to, Gas: 50000, Value: big.NewInt(1), Data: []byte{1, 2}})
+}
+
+func TestTxManager_Send(t *testing.T) {
+	backend := newPoolBackend()
+	backend.minFeeCap = big.NewInt(1000)
+	backend.nonce = 5
+	config := DefaultTxConfig()
+	config.MaxFeePerGas = big.NewInt(15)
+	config.MaxTipCap = big.NewInt(1)
+	m := testTxManager(t, backend, config)
+	ctx := context.Background()
+
+	first, err := m.Send(ctx, "first", draftTx())
+	require.Nil(t, err)
+	second, err := m.Send(ctx, "second", draftTx())
+	require.Nil(t, err)
+	require.Equal(t, uint64(5), first.Nonce)
+	require.Equal(t, uint64(6), second.Nonce)
+	require.Len(t, m.Pending(), 2)
+
+	tx := first.latest()
+	require.Equal(t, int64(15), tx.GasFeeCap().Int64())
+	require.Equal(t, int64(1), tx.GasTipCap().Int64())
+	require.Equal(t, uint64(50000), tx.Gas())
+	require.Equal(t, []byte{1, 2}, tx.Data())
+	sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(m.chainID), tx)
+	require.Nil(t, err)
+	require.Equal(t, m.from, sender)
+
+	// the fee cap leaves no room for a replacement
+	require.True(t, errors.Is(m.SpeedUp(ctx, first), ErrFeeCapReached))
+}
+
+func TestTxManager_SpeedUp(t *testing.T) {
+	backend := newPoolBackend()
+	// base fee 10 and tip 2 give a fee cap of 22, two bumps are needed
+	backend.minFeeCap = big.NewInt(30)
+	config := DefaultTxConfig()
+	config.StuckAfter = time.Nanosecond
+	config.PollInterval = time.Millisecond
+	config.Confirmations = 3
+	m := testTxManager(t, backend, config)
+	ctx := context.Background()
+
+	p, err := m.Send(ctx, "stuck", draftTx())
+	require.Nil(t, err)
+	receipt, err := m.Wait(ctx, p)
+	require.Nil(t, err)
+	require.Len(t, p.Txs, 3)
+	require.Equal(t, p.latest().Hash(), receipt.TxHash)
+	require.Equal(t, int64(22), p.Txs[0].GasFeeCap().Int64())
+	require.Equal(t, int64(27), p.Txs[1].GasFeeCap().Int64())
+	require.Equal(t, int64(33), p.Txs[2].GasFeeCap().Int64())
+	require.GreaterOrEqual(t, backend.head+1, receipt.BlockNumber.Uint64()+3)
+	require.Empty(t, m.Pending())
+}
+
+func TestTxManager_Cancel(t *testing.T) {
+	backend := newPoolBackend()
+	backend.minFeeCap = big.NewInt(25)
+	config := DefaultTxConfig()
+	config.StuckAfter = 0
+	config.PollInterval = time.Millisecond
+	m := testTxManager(t, backend, config)
+	ctx := context.Background()
+
+	p, err := m.Send(ctx, "cancelled", draftTx())
+	require.Nil(t, err)
+	require.Nil(t, m.Cancel(ctx, p))
+	cancel := p.latest()
+	require.Equal(t, m.from, *cancel.To())
+	require.Equal(t, uint64(21000), cancel.Gas())
+	require.Equal(t, p.Nonce, cancel.Nonce())
+
+	receipt, err := m.Wait(ctx, p)
+	require.NotNil(t, err)
+	require.Equal(t, cancel.Hash(), receipt.TxHash)
+}
+
+func TestTxManager_CancelTooLate(t *testing.T) {
+	backend := newPoolBackend()
+	backend.minFeeCap = big.NewInt(1000)
+	config := DefaultTxConfig()
+	config.StuckAfter = 0
+	config.PollInterval = time.Millisecond
+	m := testTxManager(t, backend, config)
+	ctx := context.Background()
+
+	p, err := m.Send(ctx, "executed", draftTx())
+	require.Nil(t, err)
+	original := p.latest()
+	require.Nil(t, m.Cancel(ctx, p))
+
+	// the original is mined before the cancellation
+	backend.mu.Lock()
+	backend.receipts[original.Hash()] = &ethtypes.Receipt{TxHash: original.Hash(), Status: ethtypes.ReceiptStatusSuccessful,
+		BlockNumber: new(big.Int).SetUint64(backend.head)}
+	backend.nonce = p.Nonce + 1
+	backend.mu.Unlock()
+
+	receipt, err := m.Wait(ctx, p)
+	require.Nil(t, err)
+	require.Equal(t, original.Hash(), receipt.TxHash)
+}
+
+func TestTxManager_Replaced(t *testing.T) {
+	backend := newPoolBackend()
+	backend.minFeeCap = big.NewInt(1000)
+	config := DefaultTxConfig()
+	config.PollInterval = time.Millisecond
+	m := testTxManager(t, backend, config)
+	ctx := context.Background()
+
+	p, err := m.Send(ctx, "replaced", draftTx())
+	require.Nil(t, err)
+	// a transaction sent around the manager takes the nonce
+	backend.mu.Lock()
+	backend.nonce = p.Nonce + 1
+	backend.mu.Unlock()
+	_, err = m.Wait(ctx, p)
+	require.True(t, errors.Is(err, ErrTxReplaced))
+}
+
+func TestTxManager_Recover(t *testing.T) {
+	store, err := OpenOrderStore(filepath.Join(t.TempDir(), "store"))
+	require.Nil(t, err)
+	defer store.Close()
+	backend := newPoolBackend()
+	backend.minFeeCap = big.NewInt(1000)
+	m := testTxManager(t, backend, DefaultTxConfig())
+	m.SetJournal(store)
+	ctx := context.Background()
+
+	first, err := m.Send(ctx, "first", draftTx())
+	require.Nil(t, err)
+	second, err := m.Send(ctx, "second", draftTx())
+	require.Nil(t, err)
+	journaled, err := store.PendingTxs(m.chainID.Int64(), m.from)
+	require.Nil(t, err)
+	require.Len(t, journaled, 2)
+
+	// the first one gets mined while the bot is down
+	backend.minFeeCap = nil
+	require.Nil(t, backend.SendTransaction(ctx, first.latest()))
+
+	restarted, err := NewTxManager(backend, m.signer, m.chainID, DefaultTxConfig())
+	require.Nil(t, err)
+	restarted.SetJournal(store)
+	recovered, err := restarted.Recover(ctx)
+	require.Nil(t, err)
+	require.Len(t, recovered, 1)
+	require.Equal(t, second.latest().Hash(), recovered[0].latest().Hash())
+	require.Equal(t, second.latest().Hash(), backend.sent[len(backend.sent)-1].Hash())
+
+	journaled, err = store.PendingTxs(m.chainID.Int64(), m.from)
+	require.Nil(t, err)
+	require.Len(t, journaled, 1)
+	require.Equal(t, "second", journaled[0].Label)
+
+	receipt, err := restarted.Wait(ctx, recovered[0])
+	require.Nil(t, err)
+	require.Equal(t, second.latest().Hash(), receipt.TxHash)
+	journaled, err = store.PendingTxs(m.chainID.Int64(), m.from)
+	require.Nil(t, err)
+	require.Empty(t, journaled)
+}
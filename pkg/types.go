@@ -2,10 +2,13 @@ package pkg
 
 import (
 	"encoding/json"
-	wallet "github.com/ethersphere/bee/pkg/crypto"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/parnurzeal/gorequest"
+	"github.com/shopspring/decimal"
 	"math/big"
 	"opensea-bot/pkg/seaport"
-	"os"
+	"strings"
 )
 
 const apiDomain = "https://api.opensea.io"
@@ -14,11 +17,6 @@ const testnetApiDomain = "https://testnets-api.opensea.io"
 const NftType721 = "erc721"
 const NftType1155 = "erc1155"
 
-var rpcURL = map[string]string{
-	"sepolia":  "https://sepolia.infura.io/v3/",
-	"ethereum": "https://mainnet.infura.io/v3/",
-}
-
 var types = `
 {
 	"EIP712Domain": [{
@@ -152,24 +150,98 @@ type OrderParameters struct {
 	Offer                           []OfferItem         `json:"offer"`
 	Consideration                   []ConsiderationItem `json:"consideration"`
 	TotalOriginalConsiderationItems int                 `json:"totalOriginalConsiderationItems"`
-	Counter                         int64               `json:"counter"`
+	Counter                         *big.Int            `json:"counter"`
 }
 
+// Item amounts, identifiers and the counter are uint256 on-chain. They are
+// encoded as decimal strings, the way OpenSea expects them.
+
 type OfferItem struct {
-	ItemType             uint8  `json:"itemType"`
-	Token                string `json:"token"`
-	IdentifierOrCriteria int64  `json:"identifierOrCriteria"`
-	StartAmount          int64  `json:"startAmount"`
-	EndAmount            int64  `json:"endAmount"`
+	ItemType             uint8    `json:"itemType"`
+	Token                string   `json:"token"`
+	IdentifierOrCriteria *big.Int `json:"identifierOrCriteria"`
+	StartAmount          *big.Int `json:"startAmount"`
+	EndAmount            *big.Int `json:"endAmount"`
 }
 
 type ConsiderationItem struct {
-	ItemType             uint8  `json:"itemType"`
-	Token                string `json:"token"`
-	IdentifierOrCriteria int64  `json:"identifierOrCriteria"`
-	StartAmount          int64  `json:"startAmount"`
-	EndAmount            int64  `json:"endAmount"`
-	Recipient            string `json:"recipient"`
+	ItemType             uint8    `json:"itemType"`
+	Token                string   `json:"token"`
+	IdentifierOrCriteria *big.Int `json:"identifierOrCriteria"`
+	StartAmount          *big.Int `json:"startAmount"`
+	EndAmount            *big.Int `json:"endAmount"`
+	Recipient            string   `json:"recipient"`
+}
+
+func (p OrderParameters) MarshalJSON() ([]byte, error) {
+	type plain OrderParameters
+	return json.Marshal(struct {
+		plain
+		Counter apiBigInt `json:"counter"`
+	}{plain(p), apiBigInt{p.Counter}})
+}
+
+func (p *OrderParameters) UnmarshalJSON(data []byte) error {
+	type plain OrderParameters
+	v := struct {
+		*plain
+		Counter apiBigInt `json:"counter"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	p.Counter = v.Counter.orZero()
+	return nil
+}
+
+func (i OfferItem) MarshalJSON() ([]byte, error) {
+	type plain OfferItem
+	return json.Marshal(struct {
+		plain
+		IdentifierOrCriteria apiBigInt `json:"identifierOrCriteria"`
+		StartAmount          apiBigInt `json:"startAmount"`
+		EndAmount            apiBigInt `json:"endAmount"`
+	}{plain(i), apiBigInt{i.IdentifierOrCriteria}, apiBigInt{i.StartAmount}, apiBigInt{i.EndAmount}})
+}
+
+func (i *OfferItem) UnmarshalJSON(data []byte) error {
+	type plain OfferItem
+	v := struct {
+		*plain
+		IdentifierOrCriteria apiBigInt `json:"identifierOrCriteria"`
+		StartAmount          apiBigInt `json:"startAmount"`
+		EndAmount            apiBigInt `json:"endAmount"`
+	}{plain: (*plain)(i)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	i.IdentifierOrCriteria, i.StartAmount, i.EndAmount = v.IdentifierOrCriteria.orZero(), v.StartAmount.orZero(), v.EndAmount.orZero()
+	return nil
+}
+
+func (i ConsiderationItem) MarshalJSON() ([]byte, error) {
+	type plain ConsiderationItem
+	return json.Marshal(struct {
+		plain
+		IdentifierOrCriteria apiBigInt `json:"identifierOrCriteria"`
+		StartAmount          apiBigInt `json:"startAmount"`
+		EndAmount            apiBigInt `json:"endAmount"`
+	}{plain(i), apiBigInt{i.IdentifierOrCriteria}, apiBigInt{i.StartAmount}, apiBigInt{i.EndAmount}})
+}
+
+func (i *ConsiderationItem) UnmarshalJSON(data []byte) error {
+	type plain ConsiderationItem
+	v := struct {
+		*plain
+		IdentifierOrCriteria apiBigInt `json:"identifierOrCriteria"`
+		StartAmount          apiBigInt `json:"startAmount"`
+		EndAmount            apiBigInt `json:"endAmount"`
+	}{plain: (*plain)(i)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	i.IdentifierOrCriteria, i.StartAmount, i.EndAmount = v.IdentifierOrCriteria.orZero(), v.StartAmount.orZero(), v.EndAmount.orZero()
+	return nil
 }
 
 type protocolData struct {
@@ -182,16 +254,29 @@ type NFT struct {
 	Identifier    string `json:"identifier"`
 	Contract      string `json:"contract"`
 	TokenStandard string `json:"token_standard"`
+	// Balance is the number of units held by the wallet, always one for
+	// ERC-721. It is nil when unknown.
+	Balance *big.Int `json:"-"`
 }
 type AccountNFTsResp struct {
 	Nfts []NFT `json:"nfts"`
 }
 
 type Account struct {
-	signer          wallet.Signer
+	signer          Signer
 	contract        *contractInfo
 	seaportInstance *seaport.Seaport
+	client          Backend
 	chainID         *big.Int
+	chain           *Chain
+	store           *OrderStore
+	http            *gorequest.SuperAgent
+	closeBackend    func()
+	txs             *TxManager
+	conduit         common.Address
+	confirmApproval ConfirmFunc
+
+	paymentTokenAddress string
 }
 
 type paymentTokenResp struct {
@@ -235,50 +320,163 @@ type BestListingResp struct {
 	} `json:"price"`
 	ProtocolData struct {
 		Parameters Parameters `json:"parameters"`
+		Signature  string     `json:"signature"`
 	} `json:"protocol_data"`
 	ProtocolAddress string `json:"protocol_address"`
 }
 type Parameters struct {
-	Offerer string `json:"offerer"`
-	Offer   []struct {
-		ItemType             int    `json:"itemType"`
-		Token                string `json:"token"`
-		IdentifierOrCriteria string `json:"identifierOrCriteria"`
-		StartAmount          string `json:"startAmount"`
-		EndAmount            string `json:"endAmount"`
-	} `json:"offer"`
-	Consideration []struct {
-		ItemType             int    `json:"itemType"`
-		Token                string `json:"token"`
-		IdentifierOrCriteria string `json:"identifierOrCriteria"`
-		StartAmount          string `json:"startAmount"`
-		EndAmount            string `json:"endAmount"`
-		Recipient            string `json:"recipient"`
-	} `json:"consideration"`
-	StartTime                       string `json:"startTime"`
-	EndTime                         string `json:"endTime"`
-	OrderType                       int    `json:"orderType"`
-	Zone                            string `json:"zone"`
-	ZoneHash                        string `json:"zoneHash"`
-	Salt                            string `json:"salt"`
-	ConduitKey                      string `json:"conduitKey"`
-	TotalOriginalConsiderationItems int    `json:"totalOriginalConsiderationItems"`
-	Counter                         int    `json:"counter"`
+	Offerer                         string                 `json:"offerer"`
+	Offer                           []apiOfferItem         `json:"offer"`
+	Consideration                   []apiConsiderationItem `json:"consideration"`
+	StartTime                       string                 `json:"startTime"`
+	EndTime                         string                 `json:"endTime"`
+	OrderType                       int                    `json:"orderType"`
+	Zone                            string                 `json:"zone"`
+	ZoneHash                        string                 `json:"zoneHash"`
+	Salt                            string                 `json:"salt"`
+	ConduitKey                      string                 `json:"conduitKey"`
+	TotalOriginalConsiderationItems int                    `json:"totalOriginalConsiderationItems"`
+	Counter                         apiBigInt              `json:"counter"`
+}
+type apiOfferItem struct {
+	ItemType             int    `json:"itemType"`
+	Token                string `json:"token"`
+	IdentifierOrCriteria string `json:"identifierOrCriteria"`
+	StartAmount          string `json:"startAmount"`
+	EndAmount            string `json:"endAmount"`
+}
+type apiConsiderationItem struct {
+	ItemType             int    `json:"itemType"`
+	Token                string `json:"token"`
+	IdentifierOrCriteria string `json:"identifierOrCriteria"`
+	StartAmount          string `json:"startAmount"`
+	EndAmount            string `json:"endAmount"`
+	Recipient            string `json:"recipient"`
 }
 type CreateListingResp struct {
-	Order struct {
-		CreatedDate    string `json:"created_date"`
-		ClosingDate    string `json:"closing_date"`
-		ListingTime    int    `json:"listing_time"`
-		ExpirationTime int    `json:"expiration_time"`
-		OrderHash      string `json:"order_hash"`
-		ProtocolData   struct {
+	Order OrderResp `json:"order"`
+}
+type OrderResp struct {
+	CreatedDate    string `json:"created_date"`
+	ClosingDate    string `json:"closing_date"`
+	ListingTime    int    `json:"listing_time"`
+	ExpirationTime int    `json:"expiration_time"`
+	OrderHash      string `json:"order_hash"`
+	ProtocolData   struct {
+		Parameters Parameters `json:"parameters"`
+		Signature  string     `json:"signature"`
+	} `json:"protocol_data"`
+	ProtocolAddress string `json:"protocol_address"`
+	CurrentPrice    string `json:"current_price"`
+	Cancelled       bool   `json:"cancelled"`
+	Finalized       bool   `json:"finalized"`
+	MarkedInvalid   bool   `json:"marked_invalid"`
+}
+
+type CancelOrderResp struct {
+	LastSignatureIssuedValidUntil string `json:"last_signature_issued_valid_until"`
+}
+
+type offerCriteria struct {
+	Collection struct {
+		Slug string `json:"slug"`
+	} `json:"collection"`
+	Contract *struct {
+		Address string `json:"address"`
+	} `json:"contract,omitempty"`
+	Trait *offerTrait `json:"trait,omitempty"`
+}
+type offerTrait struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+type BuildOfferResp struct {
+	PartialParameters struct {
+		Consideration []apiConsiderationItem `json:"consideration"`
+		Zone          string                 `json:"zone"`
+		ZoneHash      string                 `json:"zoneHash"`
+	} `json:"partialParameters"`
+	Criteria offerCriteria `json:"criteria"`
+}
+type CriteriaOfferResp struct {
+	OrderHash    string        `json:"order_hash"`
+	Chain        string        `json:"chain"`
+	Criteria     offerCriteria `json:"criteria"`
+	ProtocolData struct {
+		Parameters Parameters `json:"parameters"`
+		Signature  string     `json:"signature"`
+	} `json:"protocol_data"`
+	ProtocolAddress string `json:"protocol_address"`
+}
+
+type FulfillmentDataResp struct {
+	Protocol        string `json:"protocol"`
+	FulfillmentData struct {
+		Transaction struct {
+			Function  string          `json:"function"`
+			Chain     int             `json:"chain"`
+			To        string          `json:"to"`
+			InputData json.RawMessage `json:"input_data"`
+		} `json:"transaction"`
+		Orders []struct {
 			Parameters Parameters `json:"parameters"`
 			Signature  string     `json:"signature"`
-		} `json:"protocol_data"`
-		ProtocolAddress string `json:"protocol_address"`
-		CurrentPrice    string `json:"current_price"`
-	} `json:"order"`
+		} `json:"orders"`
+	} `json:"fulfillment_data"`
+}
+
+type fulfillmentInputData struct {
+	AdvancedOrder *struct {
+		Numerator   *apiBigInt `json:"numerator"`
+		Denominator *apiBigInt `json:"denominator"`
+		ExtraData   string     `json:"extraData"`
+	} `json:"advancedOrder"`
+	CriteriaResolvers []struct {
+		OrderIndex    apiBigInt `json:"orderIndex"`
+		Side          apiBigInt `json:"side"`
+		Index         apiBigInt `json:"index"`
+		Identifier    apiBigInt `json:"identifier"`
+		CriteriaProof []string  `json:"criteriaProof"`
+	} `json:"criteriaResolvers"`
+}
+
+// apiBigInt decodes integers OpenSea encodes either as JSON numbers or as
+// decimal or hex strings.
+type apiBigInt struct {
+	*big.Int
+}
+
+func (n *apiBigInt) UnmarshalJSON(data []byte) error {
+	value, err := parseBigInt(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	n.Int = value
+	return nil
+}
+
+func (n apiBigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.orZero().String())
+}
+
+func (n apiBigInt) orZero() *big.Int {
+	return bigOrZero(n.Int)
+}
+
+type BestOfferResp struct {
+	OrderHash string        `json:"order_hash"`
+	Chain     string        `json:"chain"`
+	Criteria  offerCriteria `json:"criteria"`
+	Price     struct {
+		Currency string `json:"currency"`
+		Decimals int    `json:"decimals"`
+		Value    string `json:"value"`
+	} `json:"price"`
+	ProtocolData struct {
+		Parameters Parameters `json:"parameters"`
+		Signature  string     `json:"signature"`
+	} `json:"protocol_data"`
+	ProtocolAddress string `json:"protocol_address"`
 }
 
 type AssetEvents struct {
@@ -303,10 +501,31 @@ type payment struct {
 	Symbol       string `json:"symbol"`
 }
 
+// itemType is the seaport item type used to pay with the token: NATIVE for the
+// zero address, ERC20 otherwise.
+func (p *paymentTokenResp) itemType() uint8 {
+	if p.Address == "" || common.HexToAddress(p.Address) == zeroAddress() {
+		return 0
+	}
+	return 1
+}
+
+func (l *BestListingResp) CurrentPrice() (decimal.Decimal, error) {
+	value, err := decimal.NewFromString(l.Price.Current.Value)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return value.Shift(int32(-l.Price.Current.Decimals)), nil
+}
+
 func (v *CreateListingResp) String() string {
 	s, _ := json.Marshal(v)
 	return string(s)
 }
+func (v *CriteriaOfferResp) String() string {
+	s, _ := json.Marshal(v)
+	return string(s)
+}
 func (n *NFT) nftType() uint8 {
 	switch n.TokenStandard {
 	case NftType1155:
@@ -317,6 +536,21 @@ func (n *NFT) nftType() uint8 {
 	return 0
 }
 
+func (n *NFT) identifier() (*big.Int, error) {
+	identifier, ok := new(big.Int).SetString(n.Identifier, 10)
+	if !ok || identifier.Sign() < 0 {
+		return nil, fmt.Errorf("invalid token identifier %q", n.Identifier)
+	}
+	return identifier, nil
+}
+
+func bigOrZero(n *big.Int) *big.Int {
+	if n == nil {
+		return new(big.Int)
+	}
+	return n
+}
+
 func (v *AccountNFTsResp) Get(identifier string) *NFT {
 	for _, nft := range v.Nfts {
 		if nft.Identifier == identifier {
@@ -325,13 +559,3 @@ func (v *AccountNFTsResp) Get(identifier string) *NFT {
 	}
 	return nil
 }
-func getOpenSeaAPI(chain string) string {
-	if chain == "ethereum" {
-		return apiDomain
-	}
-	return testnetApiDomain
-}
-
-func getRpcURL(chain string) string {
-	return rpcURL[chain] + os.Getenv("INFURA_KEY")
-}
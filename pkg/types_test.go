@@ -0,0 +1,72 @@
+package pkg
+
+import (
+	"encoding/json"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestOrderParameters_JSON(t *testing.T) {
+	identifier, _ := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	price, _ := new(big.Int).SetString("12500000000000000000", 10)
+	counter := new(big.Int).Lsh(big.NewInt(1), 128)
+	p := OrderParameters{
+		Offer: []OfferItem{
+			{ItemType: 3, IdentifierOrCriteria: identifier, StartAmount: big.NewInt(5), EndAmount: big.NewInt(5)},
+		},
+		Consideration: []ConsiderationItem{
+			{ItemType: 0, IdentifierOrCriteria: new(big.Int), StartAmount: price, EndAmount: price},
+		},
+		Counter: counter,
+	}
+
+	data, err := json.Marshal(p)
+	require.Nil(t, err)
+	require.Contains(t, string(data), `"identifierOrCriteria":"115792089237316195423570985008687907853269984665640564039457584007913129639935"`)
+	require.Contains(t, string(data), `"startAmount":"12500000000000000000"`)
+	require.Contains(t, string(data), `"counter":"340282366920938463463374607431768211456"`)
+
+	var decoded OrderParameters
+	require.Nil(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, 0, identifier.Cmp(decoded.Offer[0].IdentifierOrCriteria))
+	require.Equal(t, 0, price.Cmp(decoded.Consideration[0].EndAmount))
+	require.Equal(t, 0, counter.Cmp(decoded.Counter))
+
+	// orders stored before amounts were strings
+	legacy := `{"offer":[{"itemType":2,"identifierOrCriteria":42,"startAmount":1,"endAmount":1}],"counter":0}`
+	require.Nil(t, json.Unmarshal([]byte(legacy), &decoded))
+	require.Equal(t, int64(42), decoded.Offer[0].IdentifierOrCriteria.Int64())
+	require.Equal(t, int64(0), decoded.Counter.Int64())
+}
+
+func TestFeeConsiderations_LargeAmount(t *testing.T) {
+	var collection CollectionResp
+	require.Nil(t, json.Unmarshal([]byte(`{"fees":[{"fee":2.5,"recipient":"0x0000a26b00c1F0DF003000390027140000fAa719","required":true}]}`), &collection))
+
+	// 100 ETH in wei overflows int64
+	amount := decimal.RequireFromString("100").Shift(18)
+	considerations, total := collection.feeConsiderations(amount, 0, zeroAddress().Hex())
+	require.Len(t, considerations, 1)
+	require.Equal(t, "2500000000000000000", considerations[0].StartAmount.String())
+	require.Equal(t, "97500000000000000000", amount.Sub(total).BigInt().String())
+}
+
+func TestListingConsiderations_Quantity(t *testing.T) {
+	var collection CollectionResp
+	require.Nil(t, json.Unmarshal([]byte(`{"fees":[{"fee":2.5,"recipient":"0x0000a26b00c1F0DF003000390027140000fAa719","required":true},{"fee":5,"recipient":"0x1111111111111111111111111111111111111111","required":true}]}`), &collection))
+
+	unit := decimal.RequireFromString("0.0123456789").Shift(18)
+	token := &paymentTokenResp{Address: zeroAddress().Hex(), Decimals: 18}
+	considerations := collection.listingConsiderations(unit, unit, 7, token, "0x9d1E5C9bA1c7B8fB8a4fA3e6d1DA6e8a25E4b4A2")
+	require.Len(t, considerations, 3)
+
+	total := new(big.Int)
+	for _, item := range considerations {
+		require.Equal(t, int64(0), new(big.Int).Mod(item.StartAmount, big.NewInt(7)).Int64())
+		require.Equal(t, 0, item.StartAmount.Cmp(item.EndAmount))
+		total.Add(total, item.StartAmount)
+	}
+	require.Equal(t, unit.Mul(decimal.NewFromInt(7)).BigInt().String(), total.String())
+}
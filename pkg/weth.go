@@ -0,0 +1,163 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"opensea-bot/pkg/weth"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrBidExposure is returned when the open offers and a new one would
+	// spend more WETH than the wallet holds or the conduit may spend.
+	ErrBidExposure = errors.New("bid exposure exceeds wrapped token balance")
+)
+
+// wrappedDecimals is the precision of the native token and its wrapped
+// version.
+const wrappedDecimals = 18
+
+// Wrap deposits amount of the native token, in ether units, into the chain's
+// wrapped token.
+func (a *Account) Wrap(ctx context.Context, amount string) (*ethtypes.Receipt, error) {
+	token, wei, err := a.wrappedAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkBalance(ctx, zeroAddress(), wei); err != nil {
+		return nil, err
+	}
+	contract, err := weth.NewWETHTransactor(token, a.client)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := contract.Deposit(a.transactOpts(ctx, wei))
+	if err != nil {
+		return nil, err
+	}
+	return a.sendTx(ctx, "wrap "+amount, tx)
+}
+
+// Unwrap withdraws amount, in ether units, of the chain's wrapped token back
+// to the native token.
+func (a *Account) Unwrap(ctx context.Context, amount string) (*ethtypes.Receipt, error) {
+	token, wei, err := a.wrappedAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkBalance(ctx, token, wei); err != nil {
+		return nil, err
+	}
+	contract, err := weth.NewWETHTransactor(token, a.client)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := contract.Withdraw(a.transactOpts(ctx, nil), wei)
+	if err != nil {
+		return nil, err
+	}
+	return a.sendTx(ctx, "unwrap "+amount, tx)
+}
+
+func (a *Account) wrappedAmount(amount string) (common.Address, *big.Int, error) {
+	if a.chain.WrappedToken == "" {
+		return zeroAddress(), nil, fmt.Errorf("no wrapped native token known for chain %s", a.chain.Name)
+	}
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return zeroAddress(), nil, err
+	}
+	if !value.IsPositive() {
+		return zeroAddress(), nil, errors.New("amount must be positive")
+	}
+	return common.HexToAddress(a.chain.WrappedToken), value.Shift(wrappedDecimals).BigInt(), nil
+}
+
+func (a *Account) checkBalance(ctx context.Context, token common.Address, amount *big.Int) error {
+	balance, err := a.TokenBalance(ctx, token)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(amount) < 0 {
+		return fmt.Errorf("%w: %s of %s, need %s", ErrInsufficientBalance, balance, token.Hex(), amount)
+	}
+	return nil
+}
+
+// BidExposure sums what the wallet's open offers on the chain could spend of
+// an ERC-20 token, across every collection in the order store. Offers missing
+// from the store are not counted, it is zero without a store.
+func (a *Account) BidExposure(token common.Address) (*big.Int, error) {
+	exposure := new(big.Int)
+	if a.store == nil {
+		return exposure, nil
+	}
+	orders, err := a.store.List(OrderCreated, OrderActive)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	for _, order := range orders {
+		if order.Chain != a.contract.Chain || order.ExpireAt <= now ||
+			!strings.EqualFold(order.Parameters.Offerer, a.WalletAddress().Hex()) {
+			continue
+		}
+		for _, item := range order.Parameters.Offer {
+			if item.ItemType == 1 && common.HexToAddress(item.Token) == token {
+				exposure.Add(exposure, maxAmount(item))
+			}
+		}
+	}
+	return exposure, nil
+}
+
+// checkBidExposure refuses an offer when it and the open offers would spend
+// more of its tokens than the wallet's balance covers, and the allowance too
+// when withAllowance is set. Offers check the balance before sending any
+// approval and the allowance once approvals are settled.
+func (a *Account) checkBidExposure(ctx context.Context, items []OfferItem, withAllowance bool) error {
+	for _, item := range items {
+		if item.ItemType != 1 {
+			continue
+		}
+		token := common.HexToAddress(item.Token)
+		exposure, err := a.BidExposure(token)
+		if err != nil {
+			return err
+		}
+		exposure.Add(exposure, maxAmount(item))
+
+		balance, err := a.TokenBalance(ctx, token)
+		if err != nil {
+			return err
+		}
+		if exposure.Cmp(balance) > 0 {
+			return fmt.Errorf("%w: offers total %s of %s, balance %s", ErrBidExposure, exposure, token.Hex(), balance)
+		}
+		if !withAllowance {
+			continue
+		}
+		allowance, err := a.TokenAllowance(ctx, token)
+		if err != nil {
+			return err
+		}
+		if exposure.Cmp(allowance) > 0 {
+			return fmt.Errorf("%w: offers total %s of %s, allowance %s", ErrBidExposure, exposure, token.Hex(), allowance)
+		}
+	}
+	return nil
+}
+
+func maxAmount(item OfferItem) *big.Int {
+	if item.EndAmount.Cmp(item.StartAmount) > 0 {
+		return item.EndAmount
+	}
+	return item.StartAmount
+}
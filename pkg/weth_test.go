@@ -0,0 +1,77 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"opensea-bot/pkg/weth"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAccount_Wrap(t *testing.T) {
+	backend := newApprovalBackend(t)
+	backend.balance = big.NewInt(1e18)
+	account := testAccount(t, backend, nil)
+	ctx := context.Background()
+
+	_, err := account.Wrap(ctx, "0.25")
+	require.Nil(t, err)
+	require.Len(t, backend.sent, 1)
+	wethABI, err := weth.WETHMetaData.GetAbi()
+	require.Nil(t, err)
+	deposit, err := wethABI.Pack("deposit")
+	require.Nil(t, err)
+	require.Equal(t, common.HexToAddress(account.chain.WrappedToken), *backend.sent[0].To())
+	require.Equal(t, deposit, backend.sent[0].Data())
+	require.Equal(t, "250000000000000000", backend.sent[0].Value().String())
+
+	_, err = account.Unwrap(ctx, "2")
+	require.True(t, errors.Is(err, ErrInsufficientBalance))
+	_, err = account.Wrap(ctx, "0")
+	require.NotNil(t, err)
+	require.Len(t, backend.sent, 1)
+}
+
+func TestAccount_BidExposure(t *testing.T) {
+	store, err := OpenOrderStore(filepath.Join(t.TempDir(), "store"))
+	require.Nil(t, err)
+	defer store.Close()
+	backend := newApprovalBackend(t)
+	backend.balance = big.NewInt(10)
+	account := approvalAccount(t, backend)
+	account.SetOrderStore(store)
+	ctx := context.Background()
+
+	bid := func(amount int64) []OfferItem {
+		return []OfferItem{{ItemType: 1, Token: testWETH.Hex(), StartAmount: big.NewInt(amount), EndAmount: big.NewInt(amount)}}
+	}
+	expireAt := time.Now().Add(time.Hour).Unix()
+	for hash, order := range map[string]*StoredOrder{
+		"open":    {Status: OrderActive, ExpireAt: expireAt, Parameters: OrderParameters{Offerer: account.WalletAddress().Hex(), Offer: bid(1)}},
+		"expired": {Status: OrderActive, ExpireAt: 1, Parameters: OrderParameters{Offerer: account.WalletAddress().Hex(), Offer: bid(4)}},
+		"filled":  {Status: OrderFilled, ExpireAt: expireAt, Parameters: OrderParameters{Offerer: account.WalletAddress().Hex(), Offer: bid(4)}},
+		"other":   {Status: OrderActive, ExpireAt: expireAt, Parameters: OrderParameters{Offerer: testContract, Offer: bid(4)}},
+	} {
+		order.OrderHash, order.Chain, order.Side = hash, "sepolia", SideOffer
+		require.Nil(t, store.Put(order))
+	}
+
+	exposure, err := account.BidExposure(testWETH)
+	require.Nil(t, err)
+	require.Equal(t, int64(1), exposure.Int64())
+
+	// the allowance of 2 is the limit, not the balance of 10
+	require.Nil(t, account.checkBidExposure(ctx, bid(1), true))
+	require.True(t, errors.Is(account.checkBidExposure(ctx, bid(2), true), ErrBidExposure))
+	// before approving only the balance counts
+	allowanceCalls := backend.calls["allowance"]
+	require.Nil(t, account.checkBidExposure(ctx, bid(2), false))
+
+	backend.balance = big.NewInt(1)
+	require.True(t, errors.Is(account.checkBidExposure(ctx, bid(1), false), ErrBidExposure))
+	require.Equal(t, allowanceCalls, backend.calls["allowance"])
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"opensea-bot/pkg"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func runCommand(args []string) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	configPath := fs.String("config", "bot.yaml", "path to the bot configuration (yaml or json)")
+	dryRun := fs.Bool("dry-run", false, "log planned orders without posting them")
+	_ = fs.Parse(args)
+
+	cfg, err := pkg.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := pkg.NewBot(cfg, *dryRun).Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
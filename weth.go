@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/shopspring/decimal"
+	"log"
+	"math/big"
+	"opensea-bot/pkg"
+	"strings"
+)
+
+func wethCommand(args []string) {
+	fs := flag.NewFlagSet("weth", flag.ExitOnError)
+	contract := fs.String("contract", "", "nft contract address the wallet trades")
+	chain := fs.String("chain", "ethereum", "chain of the contract")
+	wrap := fs.String("wrap", "", "amount of the native token to wrap")
+	unwrap := fs.String("unwrap", "", "amount of the wrapped token to unwrap")
+	storePath := fs.String("store", "", "order store, to report the exposure of the open offers")
+	rpc := fs.String("rpc", "", "comma separated rpc endpoints, defaults to the chain's endpoint")
+	keystore := fs.String("keystore", "", "geth keystore file of the wallet, the passphrase is prompted for")
+	external := fs.String("signer", "", "endpoint of a Clef compatible external signer")
+	_ = fs.Parse(args)
+
+	ctx := context.Background()
+	var endpoints []string
+	if *rpc != "" {
+		endpoints = strings.Split(*rpc, ",")
+	}
+	opts := []pkg.Option{pkg.WithContract(*contract), pkg.WithChain(*chain), pkg.WithRPC(endpoints...)}
+	if *keystore != "" || *external != "" {
+		signer, err := pkg.OpenSigner(ctx, &pkg.WalletConfig{Keystore: *keystore, External: *external})
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, pkg.WithSigner(signer))
+	}
+	account, err := pkg.NewAccount(ctx, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer account.Close()
+
+	switch {
+	case *wrap != "" && *unwrap != "":
+		log.Fatal("-wrap and -unwrap are exclusive")
+	case *wrap != "":
+		receipt, err := account.Wrap(ctx, *wrap)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("wrapped %s in block %s", *wrap, receipt.BlockNumber)
+	case *unwrap != "":
+		receipt, err := account.Unwrap(ctx, *unwrap)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("unwrapped %s in block %s", *unwrap, receipt.BlockNumber)
+	}
+
+	chainInfo, err := pkg.LookupChain(*chain)
+	if err != nil {
+		log.Fatal(err)
+	}
+	native, err := account.Balance(ctx, common.Address{}.Hex())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("native\t%s\n", native)
+	if chainInfo.WrappedToken == "" {
+		return
+	}
+	wrapped, err := account.Balance(ctx, chainInfo.WrappedToken)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("wrapped\t%s\n", wrapped)
+	allowance, err := account.TokenAllowance(ctx, wrapped.Token)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("allowance\t%s\n", units(allowance, wrapped.Decimals))
+
+	if *storePath == "" {
+		return
+	}
+	store, err := pkg.OpenOrderStore(*storePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer store.Close()
+	account.SetOrderStore(store)
+	exposure, err := account.BidExposure(common.HexToAddress(chainInfo.WrappedToken))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("offers\t%s\n", units(exposure, wrapped.Decimals))
+}
+
+func units(amount *big.Int, decimals int) string {
+	if amount.Cmp(math.MaxBig256) == 0 {
+		return "unlimited"
+	}
+	return decimal.NewFromBigInt(amount, -int32(decimals)).String()
+}